@@ -24,14 +24,29 @@ func MarshalIndentCompact(v interface{}, prefix, indent string, lineLen int) ([]
 		return b, nil
 	}
 
+	// Unmarshal JSON payload into ordered map to recursively walk the document.
+	i, err := unmarshalOrdered(b)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create first level padding.
+	pad := append([]byte(prefix), []byte(indent)...)
+
+	// Call recursive function to walk the document.
+	return marshalIndentCompact(i, indent, pad, lineLen)
+}
+
+// unmarshalOrdered decodes JSON document with objects as orderedmap.OrderedMap to keep properties order.
+func unmarshalOrdered(data []byte) (interface{}, error) {
 	m := orderedmap.New()
+	m.SetEscapeHTML(false)
 
 	// Create a temporary JSON object to make sure it can be unmarshaled into a map.
-	tmpMap := append([]byte(`{"t":`), b...)
+	tmpMap := append([]byte(`{"t":`), data...)
 	tmpMap = append(tmpMap, '}')
 
-	// Unmarshal JSON payload into ordered map to recursively walk the document.
-	err = json.Unmarshal(tmpMap, m)
+	err := json.Unmarshal(tmpMap, m)
 	if err != nil {
 		return nil, err
 	}
@@ -41,11 +56,7 @@ func MarshalIndentCompact(v interface{}, prefix, indent string, lineLen int) ([]
 		return nil, errors.New("no value for this key")
 	}
 
-	// Create first level padding.
-	pad := append([]byte(prefix), []byte(indent)...)
-
-	// Call recursive function to walk the document.
-	return marshalIndentCompact(i, indent, pad, lineLen)
+	return i, nil
 }
 
 func marshalUnescaped(v interface{}) ([]byte, error) {
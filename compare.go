@@ -3,17 +3,19 @@ package assertjson
 import (
 	"bytes"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/bool64/shared"
 	"github.com/swaggest/assertjson/diff"
 )
 
-func (c Comparer) varCollected(s string, v interface{}) bool {
-	if c.Vars != nil && c.Vars.IsVar(s) {
+// varCollected sets unknown variable with actual value.
+func (c *comparison) varCollected(s string, v interface{}) bool {
+	if c.isVar(s) {
 		if _, found := c.Vars.Get(s); !found {
 			if n, ok := v.(json.Number); ok {
 				v = shared.DecodeJSONNumber(n)
@@ -22,6 +24,7 @@ func (c Comparer) varCollected(s string, v interface{}) bool {
 			}
 
 			c.Vars.Set(s, v)
+			c.collected = append(c.collected, s)
 
 			return true
 		}
@@ -30,34 +33,103 @@ func (c Comparer) varCollected(s string, v interface{}) bool {
 	return false
 }
 
-func (c Comparer) filterDeltas(deltas []diff.Delta, ignoreAdded bool) []diff.Delta {
+// comparison keeps state of a single comparison.
+type comparison struct {
+	Comparer
+
+	ignoreAdded bool
+
+	// dryRun disables collection of variables.
+	dryRun bool
+
+	// ignorePaths are parsed selectors of IgnorePaths.
+	ignorePaths []diff.Selector
+
+	// differ is shared by all comparisons of values, so that its selectors are parsed once.
+	differ *diff.Differ
+
+	// mismatches explain differences that were not accepted by placeholders.
+	mismatches []string
+
+	// origins are names of variables by JSON Pointer of substituted expected values.
+	origins map[string]string
+
+	// collected are names of variables that were set with actual values.
+	collected []string
+}
+
+// newComparison parses selectors of configuration once for a comparison.
+func (c Comparer) newComparison(ignoreAdded bool) (*comparison, error) {
+	cmp := &comparison{Comparer: c, ignoreAdded: ignoreAdded}
+
+	for _, s := range c.IgnorePaths {
+		sel, err := diff.ParseSelectorStrict(s)
+		if err != nil {
+			return nil, fmt.Errorf("IgnorePaths: %w", err)
+		}
+
+		cmp.ignorePaths = append(cmp.ignorePaths, sel)
+	}
+
+	for _, s := range c.DifferConfig.UnorderedArrayPaths {
+		if _, err := diff.ParseSelectorStrict(s); err != nil {
+			return nil, fmt.Errorf("UnorderedArrayPaths: %w", err)
+		}
+	}
+
+	keys := make([]string, 0, len(c.DifferConfig.ArrayKeys))
+	for s := range c.DifferConfig.ArrayKeys {
+		keys = append(keys, s)
+	}
+
+	sort.Strings(keys)
+
+	for _, s := range keys {
+		if _, err := diff.ParseSelectorStrict(s); err != nil {
+			return nil, fmt.Errorf("ArrayKeys: %w", err)
+		}
+	}
+
+	cmp.differ = diff.NewWithConfig(c.DifferConfig)
+
+	return cmp, nil
+}
+
+func (c *comparison) filterDeltas(deltas []diff.Delta, path []string) []diff.Delta {
 	result := make([]diff.Delta, 0, len(deltas))
 
 	for _, delta := range deltas {
+		deltaPath := path
+		if p := deltaPosition(delta); p != (diff.Root{}) {
+			deltaPath = append(path[:len(path):len(path)], p.String())
+		}
+
+		if c.pathIgnored(deltaPath) {
+			continue
+		}
+
 		switch v := delta.(type) {
 		case *diff.Modified:
-			if c.IgnoreDiff == "" && c.Vars == nil {
-				break
+			if c.modifiedAccepted(v, deltaPath) {
+				continue
+			}
+		case *diff.TextDiff:
+			if c.modifiedAccepted(&v.Modified, deltaPath) {
+				continue
 			}
 
-			if s, ok := v.OldValue.(string); ok {
-				if s == c.IgnoreDiff { // discarding ignored diff
-					continue
-				}
-
-				if c.varCollected(s, v.NewValue) {
-					continue
-				}
+			if c.isPattern(v.OldValue.(string)) {
+				delta = &v.Modified // Showing pattern and actual value instead of text diff.
 			}
 		case *diff.Object:
-			v.Deltas = c.filterDeltas(v.Deltas, ignoreAdded)
+			v.Deltas = c.filterDeltas(v.Deltas, deltaPath)
 			if len(v.Deltas) == 0 {
 				continue
 			}
 
 			delta = v
 		case *diff.Array:
-			v.Deltas = c.filterDeltas(v.Deltas, ignoreAdded)
+			v.Deltas = c.filterDeltas(v.Deltas, deltaPath)
 			if len(v.Deltas) == 0 {
 				continue
 			}
@@ -65,9 +137,18 @@ func (c Comparer) filterDeltas(deltas []diff.Delta, ignoreAdded bool) []diff.Del
 			delta = v
 
 		case *diff.Added:
-			if ignoreAdded {
+			if c.ignoreAdded {
 				continue
 			}
+
+		case *diff.Moved:
+			if d, ok := v.Delta.(diff.Delta); ok {
+				if filtered := c.filterDeltas([]diff.Delta{d}, path); len(filtered) > 0 {
+					v.Delta = filtered[0]
+				} else {
+					v.Delta = nil
+				}
+			}
 		}
 
 		result = append(result, delta)
@@ -76,6 +157,188 @@ func (c Comparer) filterDeltas(deltas []diff.Delta, ignoreAdded bool) []diff.Del
 	return result
 }
 
+// modifiedAccepted checks if modified value is allowed by expected value.
+func (c *comparison) modifiedAccepted(v *diff.Modified, path []string) bool {
+	s, ok := v.OldValue.(string)
+	if !ok {
+		return false
+	}
+
+	if c.IgnoreDiff != "" && s == c.IgnoreDiff { // discarding ignored diff
+		return true
+	}
+
+	if c.Placeholders != nil {
+		if check, found := c.Placeholders.Get(s); found {
+			if err := check(v.NewValue); err != nil {
+				c.mismatch(path, err.Error())
+
+				return false
+			}
+
+			return true
+		}
+	}
+
+	if pattern, ok := c.regexpPattern(s); ok {
+		if err := matchRegexp(pattern, v.NewValue); err != nil {
+			c.mismatch(path, err.Error())
+
+			return false
+		}
+
+		return true
+	}
+
+	if c.isTemplate(s) {
+		return c.templateAccepted(s, v.NewValue, path)
+	}
+
+	if c.isVar(s) {
+		if value, found := c.Vars.Get(s); found {
+			if c.varEqual(value, v.NewValue) {
+				return true
+			}
+
+			c.varConflict(s, value, v.NewValue, path)
+
+			return false
+		}
+	}
+
+	if c.dryRun {
+		return c.isVar(s)
+	}
+
+	return c.varCollected(s, v.NewValue)
+}
+
+// varEqual checks if value of variable is equal to actual value.
+func (c *comparison) varEqual(value, actual interface{}) bool {
+	j, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+
+	var decoded interface{}
+	if err := unmarshal(j, &decoded); err != nil {
+		return false
+	}
+
+	return !c.compare(decoded, actual).Modified()
+}
+
+// varConflict adds explanation of actual value that is different from value of variable.
+func (c *comparison) varConflict(name string, value, actual interface{}, path []string) {
+	c.mismatches = append(c.mismatches, fmt.Sprintf("variable %s bound to %s but found %s at %s",
+		name, jsonValue(value), jsonValue(actual), diff.Pointer(path...)))
+}
+
+// jsonValue renders value as JSON.
+func jsonValue(v interface{}) string {
+	j, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+
+	return string(j)
+}
+
+// isPattern checks if expected value is a placeholder, a regexp or a template with variables.
+func (c Comparer) isPattern(s string) bool {
+	if c.Placeholders != nil {
+		if _, found := c.Placeholders.Get(s); found {
+			return true
+		}
+	}
+
+	if _, ok := c.regexpPattern(s); ok {
+		return true
+	}
+
+	return c.isTemplate(s)
+}
+
+// regexpPattern returns regular expression if expected value is a regexp placeholder.
+func (c Comparer) regexpPattern(s string) (string, bool) {
+	if c.RegexpPrefix == "" || len(s) < len(c.RegexpPrefix)+len(c.RegexpSuffix) {
+		return "", false
+	}
+
+	if !strings.HasPrefix(s, c.RegexpPrefix) || !strings.HasSuffix(s, c.RegexpSuffix) {
+		return "", false
+	}
+
+	return s[len(c.RegexpPrefix) : len(s)-len(c.RegexpSuffix)], true
+}
+
+func matchRegexp(pattern string, v interface{}) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid regexp: %w", err)
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("expected string matching %s, got %s", pattern, jsonType(v))
+	}
+
+	if !re.MatchString(s) {
+		return fmt.Errorf("%q does not match %s", s, pattern)
+	}
+
+	return nil
+}
+
+// explanation lists mismatches and variables collected during comparison.
+func (c *comparison) explanation() string {
+	var res strings.Builder
+
+	for _, m := range c.mismatches {
+		res.WriteString(m + "\n")
+	}
+
+	if len(c.collected) > 0 {
+		res.WriteString("collected variables:\n")
+
+		for _, name := range c.collected {
+			value, _ := c.Vars.Get(name)
+			res.WriteString(name + ": " + jsonValue(value) + "\n")
+		}
+	}
+
+	return res.String()
+}
+
+// mismatch adds explanation of a difference at location.
+func (c *comparison) mismatch(path []string, msg string) {
+	if len(path) > 0 {
+		msg = diff.Pointer(path...) + ": " + msg
+	}
+
+	c.mismatches = append(c.mismatches, msg)
+}
+
+// pathIgnored checks if location matches any of IgnorePaths selectors.
+func (c *comparison) pathIgnored(path []string) bool {
+	for _, s := range c.ignorePaths {
+		if s.Match(path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// deltaPosition returns position of delta in actual document, or in expected document for deleted values.
+func deltaPosition(delta diff.Delta) diff.Position {
+	if d, ok := delta.(diff.PostDelta); ok {
+		return d.PostPosition()
+	}
+
+	return delta.(diff.PreDelta).PrePosition()
+}
+
 type df struct {
 	deltas []diff.Delta
 }
@@ -88,44 +351,99 @@ func (df *df) Modified() bool {
 	return len(df.deltas) > 0
 }
 
-func (c Comparer) filterExpected(expected []byte) ([]byte, error) {
-	if c.Vars != nil {
-		for k, v := range c.Vars.GetAll() {
-			j, err := json.Marshal(v)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal var %s: %w", k, err) // Not wrapping to support go1.12.
-			}
+// substituteVars replaces string values that refer to known variables with their values.
+func (c *comparison) substituteVars(v interface{}, path []string) (interface{}, error) {
+	if c.Vars == nil {
+		return v, nil
+	}
+
+	switch v := v.(type) {
+	case string:
+		if c.isTemplate(v) {
+			c.origin(path, c.knownTemplateVars(v)...)
 
-			expected = bytes.ReplaceAll(expected, []byte(`"`+k+`"`), j)
+			return c.interpolate(v)
 		}
-	}
 
-	return expected, nil
-}
+		if !c.isVar(v) {
+			return v, nil
+		}
 
-func (c Comparer) compare(expDecoded, actDecoded interface{}) (diff.Diff, error) {
-	switch v := expDecoded.(type) {
-	case []interface{}:
-		if actArray, ok := actDecoded.([]interface{}); ok {
-			return diff.New().CompareArrays(v, actArray), nil
+		value, found := c.Vars.Get(v)
+		if !found {
+			return v, nil
+		}
+
+		j, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal var %s: %w", v, err)
+		}
+
+		var decoded interface{}
+		if err := unmarshal(j, &decoded); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal var %s: %w", v, err)
 		}
 
-		return nil, errors.New("types mismatch, array expected")
+		c.origin(path, v)
+
+		return decoded, nil
 
 	case map[string]interface{}:
-		if actObject, ok := actDecoded.(map[string]interface{}); ok {
-			return diff.New().CompareObjects(v, actObject), nil
+		for k, child := range v {
+			childPath := append(path[:len(path):len(path)], k)
+
+			if c.isVar(k) {
+				return nil, fmt.Errorf("variable %s is used as object key at %s, only values are supported",
+					k, diff.Pointer(childPath...))
+			}
+
+			child, err := c.substituteVars(child, childPath)
+			if err != nil {
+				return nil, err
+			}
+
+			v[k] = child
 		}
 
-		return nil, errors.New("types mismatch, object expected")
+	case []interface{}:
+		for i, child := range v {
+			child, err := c.substituteVars(child, append(path[:len(path):len(path)], strconv.Itoa(i)))
+			if err != nil {
+				return nil, err
+			}
+
+			v[i] = child
+		}
+	}
+
+	return v, nil
+}
+
+// origin remembers variables that provide expected value at location.
+func (c *comparison) origin(path []string, names ...string) {
+	if len(names) == 0 {
+		return
+	}
+
+	if c.origins == nil {
+		c.origins = make(map[string]string)
+	}
+
+	unique := names[:0:0]
+	seen := make(map[string]bool, len(names))
 
-	default:
-		if !reflect.DeepEqual(expDecoded, actDecoded) { // scalar value comparison
-			return nil, fmt.Errorf("values %v and %v are not equal", expDecoded, actDecoded)
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
 		}
 	}
 
-	return nil, nil
+	c.origins[diff.Pointer(path...)] = strings.Join(unique, ", ")
+}
+
+func (c *comparison) compare(expDecoded, actDecoded interface{}) diff.Diff {
+	return c.differ.CompareValues(expDecoded, actDecoded)
 }
 
 func unmarshal(data []byte, decoded interface{}) error {
@@ -136,59 +454,79 @@ func unmarshal(data []byte, decoded interface{}) error {
 }
 
 func (c Comparer) fail(expected, actual []byte, ignoreAdded bool) error {
+	res, err := c.compareBytes(expected, actual, ignoreAdded)
+	if err != nil {
+		return err
+	}
+
+	if res.Equal() {
+		return nil
+	}
+
+	return &NotEqualError{Result: res}
+}
+
+func (c Comparer) compareBytes(expected, actual []byte, ignoreAdded bool) (*Result, error) {
+	c.Vars = c.varStore()
+
 	var expDecoded, actDecoded interface{}
 
-	expected, err := c.filterExpected(expected)
+	err := unmarshal(expected, &expDecoded)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to unmarshal expected:\n%wv", err)
 	}
 
-	err = unmarshal(expected, &expDecoded)
+	cmp, err := c.newComparison(ignoreAdded)
 	if err != nil {
-		return fmt.Errorf("failed to unmarshal expected:\n%wv", err)
+		return nil, err
 	}
 
-	err = unmarshal(actual, &actDecoded)
+	expDecoded, err = cmp.substituteVars(expDecoded, nil)
 	if err != nil {
-		return fmt.Errorf("failed to unmarshal actual:\n%wv", err)
+		return nil, err
 	}
 
-	if s, ok := expDecoded.(string); ok && c.Vars != nil && c.Vars.IsVar(s) {
-		if c.varCollected(s, actDecoded) {
-			return nil
-		}
+	err = unmarshal(actual, &actDecoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal actual:\n%wv", err)
+	}
 
-		if v, found := c.Vars.Get(s); found {
-			expDecoded = v
+	if c.Strict {
+		if err := checkStrict(actual); err != nil {
+			return nil, fmt.Errorf("invalid actual: %w", err)
 		}
 	}
 
-	diffValue, err := c.compare(expDecoded, actDecoded)
-	if err != nil {
-		return err
+	if s, ok := expDecoded.(string); ok && cmp.varCollected(s, actDecoded) {
+		return &Result{}, nil
 	}
 
-	if diffValue == nil {
-		return nil
+	if ignoreAdded && c.ArrayMatching != ArrayMatchExact {
+		actDecoded = cmp.alignArrays(expDecoded, actDecoded, nil)
 	}
 
+	diffValue := cmp.compare(expDecoded, actDecoded)
 	if !diffValue.Modified() {
-		return nil
+		return &Result{}, nil
 	}
 
-	diffValue = &df{deltas: c.filterDeltas(diffValue.Deltas(), ignoreAdded)}
+	diffValue = &df{deltas: cmp.filterDeltas(diffValue.Deltas(), nil)}
 	if !diffValue.Modified() {
-		return nil
+		return &Result{}, nil
 	}
 
-	diffText, err := diff.NewASCIIFormatter(expDecoded, c.FormatterConfig).Format(diffValue)
+	diffText, err := c.formatDiff(expDecoded, diffValue, cmp.origins)
 	if err != nil {
-		return fmt.Errorf("failed to format diff:\n%wv", err)
+		return nil, err
 	}
 
-	diffText = c.reduceDiff(diffText)
+	diffText += cmp.explanation()
 
-	return errors.New("not equal:\n" + diffText)
+	return &Result{
+		Deltas:  diffValue.Deltas(),
+		Changes: diff.Changes(diffValue.Deltas()),
+		Diff:    diffText,
+	}, nil
 }
 
 func (c Comparer) reduceDiff(diffText string) string {
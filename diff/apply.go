@@ -0,0 +1,371 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Errors of patch application.
+var (
+	ErrTestFailed   = errors.New("test failed")
+	ErrPathNotFound = errors.New("path not found")
+	ErrInvalidPatch = errors.New("invalid patch")
+)
+
+// PatchError describes a failed operation of JSON Patch.
+type PatchError struct {
+	// Index is a position of operation in patch document.
+	Index int
+	Op    string
+	Path  string
+	Err   error
+}
+
+// Error returns error message.
+func (e *PatchError) Error() string {
+	return fmt.Sprintf("operation %d (%s %q) failed: %v", e.Index, e.Op, e.Path, e.Err)
+}
+
+// Unwrap returns underlying error.
+func (e *PatchError) Unwrap() error {
+	return e.Err
+}
+
+type patchOperation struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// ApplyJSONPatch applies JSON Patch (RFC 6902) to JSON document.
+//
+// Operations are applied one by one, failed operation is reported with *PatchError.
+func ApplyJSONPatch(doc, patch []byte) ([]byte, error) {
+	var ops []patchOperation
+
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	value, err := decodeValue(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode document: %w", err)
+	}
+
+	for i, op := range ops {
+		if op.Path == nil {
+			return nil, &PatchError{Index: i, Op: op.Op, Err: fmt.Errorf("%w: missing path", ErrInvalidPatch)}
+		}
+
+		value, err = applyOperation(value, op)
+		if err != nil {
+			return nil, &PatchError{Index: i, Op: op.Op, Path: *op.Path, Err: err}
+		}
+	}
+
+	return marshalJSON(value)
+}
+
+// ApplyMergePatch applies JSON Merge Patch (RFC 7386) to JSON document.
+func ApplyMergePatch(doc, patch []byte) ([]byte, error) {
+	value, err := decodeValue(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode document: %w", err)
+	}
+
+	p, err := decodeValue(patch)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	return marshalJSON(mergeValue(value, p))
+}
+
+// marshalJSON encodes value without escaping of HTML characters, so that values like "<ignore-diff>" are kept as is.
+func marshalJSON(v interface{}) ([]byte, error) {
+	b := bytes.NewBuffer(nil)
+	enc := json.NewEncoder(b)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n")), nil
+}
+
+func mergeValue(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = make(map[string]interface{}, len(p))
+	}
+
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+		} else {
+			t[k] = mergeValue(t[k], v)
+		}
+	}
+
+	return t
+}
+
+func decodeValue(data []byte) (interface{}, error) {
+	var v interface{}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+func applyOperation(doc interface{}, op patchOperation) (interface{}, error) {
+	path, err := ParsePointer(*op.Path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	var value, from interface{}
+
+	switch op.Op {
+	case JSONPatchAdd, JSONPatchReplace, JSONPatchTest:
+		if len(op.Value) == 0 {
+			return nil, fmt.Errorf("%w: missing value", ErrInvalidPatch)
+		}
+
+		if value, err = decodeValue(op.Value); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+		}
+	case JSONPatchMove, JSONPatchCopy:
+		if op.From == nil {
+			return nil, fmt.Errorf("%w: missing from", ErrInvalidPatch)
+		}
+
+		fromPath, err := ParsePointer(*op.From)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+		}
+
+		if from, err = getValue(doc, fromPath); err != nil {
+			return nil, err
+		}
+
+		if op.Op == JSONPatchCopy {
+			return addValue(doc, path, copyValue(from))
+		}
+
+		if len(path) > len(fromPath) && Pointer(path[:len(fromPath)]...) == Pointer(fromPath...) {
+			return nil, fmt.Errorf("%w: can not move %s into its child", ErrInvalidPatch, *op.From)
+		}
+
+		if doc, err = removeValue(doc, fromPath); err != nil {
+			return nil, err
+		}
+
+		return addValue(doc, path, from)
+	case JSONPatchRemove:
+		return removeValue(doc, path)
+	default:
+		return nil, fmt.Errorf("%w: unknown operation %q", ErrInvalidPatch, op.Op)
+	}
+
+	switch op.Op {
+	case JSONPatchAdd:
+		return addValue(doc, path, value)
+	case JSONPatchReplace:
+		if len(path) == 0 {
+			return value, nil
+		}
+
+		if doc, err = removeValue(doc, path); err != nil {
+			return nil, err
+		}
+
+		return addValue(doc, path, value)
+	default:
+		actual, err := getValue(doc, path)
+		if err != nil {
+			return nil, err
+		}
+
+		if NewWithConfig(DifferConfig{SemanticNumbers: true}).CompareValues(value, actual).Modified() {
+			expected, _ := marshalJSON(value)
+			found, _ := marshalJSON(actual)
+
+			return nil, fmt.Errorf("%w: expected %s, got %s", ErrTestFailed, expected, found)
+		}
+
+		return doc, nil
+	}
+}
+
+func getValue(doc interface{}, path []string) (interface{}, error) {
+	for i := range path {
+		v, err := childValue(doc, path, i)
+		if err != nil {
+			return nil, err
+		}
+
+		doc = v
+	}
+
+	return doc, nil
+}
+
+func addValue(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	return updateValue(doc, path, 0, func(container interface{}) (interface{}, error) {
+		token := path[len(path)-1]
+
+		switch c := container.(type) {
+		case map[string]interface{}:
+			c[token] = value
+
+			return c, nil
+		case []interface{}:
+			i := len(c)
+
+			if token != "-" {
+				var err error
+				if i, err = arrayIndex(c, path, len(path)-1, true); err != nil {
+					return nil, err
+				}
+			}
+
+			return append(c[:i], append([]interface{}{value}, c[i:]...)...), nil
+		default:
+			return nil, fmt.Errorf("%w: %s is not a container", ErrPathNotFound, Pointer(path[:len(path)-1]...))
+		}
+	})
+}
+
+func removeValue(doc interface{}, path []string) (interface{}, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("%w: can not remove document root", ErrInvalidPatch)
+	}
+
+	return updateValue(doc, path, 0, func(container interface{}) (interface{}, error) {
+		if _, err := childValue(container, path, len(path)-1); err != nil {
+			return nil, err
+		}
+
+		switch c := container.(type) {
+		case map[string]interface{}:
+			delete(c, path[len(path)-1])
+
+			return c, nil
+		default:
+			a := container.([]interface{})
+			i, _ := arrayIndex(a, path, len(path)-1, false)
+
+			return append(a[:i:i], a[i+1:]...), nil
+		}
+	})
+}
+
+// updateValue replaces parent container of a value at path with result of update.
+func updateValue(
+	doc interface{},
+	path []string,
+	depth int,
+	update func(container interface{}) (interface{}, error),
+) (interface{}, error) {
+	if depth == len(path)-1 {
+		return update(doc)
+	}
+
+	child, err := childValue(doc, path, depth)
+	if err != nil {
+		return nil, err
+	}
+
+	if child, err = updateValue(child, path, depth+1, update); err != nil {
+		return nil, err
+	}
+
+	switch c := doc.(type) {
+	case map[string]interface{}:
+		c[path[depth]] = child
+	case []interface{}:
+		i, _ := arrayIndex(c, path, depth, false)
+		c[i] = child
+	}
+
+	return doc, nil
+}
+
+// childValue returns value of container at path[depth].
+func childValue(container interface{}, path []string, depth int) (interface{}, error) {
+	switch c := container.(type) {
+	case map[string]interface{}:
+		v, found := c[path[depth]]
+		if !found {
+			return nil, fmt.Errorf("%w: %s", ErrPathNotFound, Pointer(path[:depth+1]...))
+		}
+
+		return v, nil
+	case []interface{}:
+		i, err := arrayIndex(c, path, depth, false)
+		if err != nil {
+			return nil, err
+		}
+
+		return c[i], nil
+	default:
+		return nil, fmt.Errorf("%w: %s is not a container", ErrPathNotFound, Pointer(path[:depth]...))
+	}
+}
+
+// arrayIndex parses array index token at path[depth], end of array is allowed for insertion.
+func arrayIndex(a []interface{}, path []string, depth int, allowEnd bool) (int, error) {
+	token := path[depth]
+
+	i, err := strconv.Atoi(token)
+	if err != nil || token[0] < '0' || token[0] > '9' || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("%w: invalid array index %s", ErrPathNotFound, Pointer(path[:depth+1]...))
+	}
+
+	if i > len(a) || (i == len(a) && !allowEnd) {
+		return 0, fmt.Errorf("%w: array index out of bounds %s", ErrPathNotFound, Pointer(path[:depth+1]...))
+	}
+
+	return i, nil
+}
+
+func copyValue(v interface{}) interface{} {
+	switch c := v.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(c))
+		for k, v := range c {
+			res[k] = copyValue(v)
+		}
+
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(c))
+		for i, v := range c {
+			res[i] = copyValue(v)
+		}
+
+		return res
+	default:
+		return v
+	}
+}
@@ -0,0 +1,157 @@
+package diff_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson/diff"
+)
+
+func TestApplyJSONPatch(t *testing.T) {
+	for _, tc := range []struct {
+		doc, patch string
+		result     string
+		err        string
+	}{
+		{
+			doc:    `{"foo":"bar"}`,
+			patch:  `[{"op":"add","path":"/baz","value":"qux"}]`,
+			result: `{"baz":"qux","foo":"bar"}`,
+		},
+		{
+			doc:    `{"foo":["bar","baz"]}`,
+			patch:  `[{"op":"add","path":"/foo/1","value":"qux"},{"op":"add","path":"/foo/-","value":null}]`,
+			result: `{"foo":["bar","qux","baz",null]}`,
+		},
+		{
+			doc:    `{"baz":"qux","foo":["bar","qux","baz"]}`,
+			patch:  `[{"op":"remove","path":"/baz"},{"op":"remove","path":"/foo/1"}]`,
+			result: `{"foo":["bar","baz"]}`,
+		},
+		{
+			doc:    `{"baz":"qux","foo":"bar"}`,
+			patch:  `[{"op":"replace","path":"/baz","value":"boo"}]`,
+			result: `{"baz":"boo","foo":"bar"}`,
+		},
+		{
+			doc:    `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			patch:  `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			result: `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`,
+		},
+		{
+			doc:    `{"foo":["all","grass","cows","eat"]}`,
+			patch:  `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`,
+			result: `{"foo":["all","cows","eat","grass"]}`,
+		},
+		{
+			doc:    `{"a/b":{"m~n":[1]}}`,
+			patch:  `[{"op":"copy","from":"/a~1b/m~0n","path":"/c"},{"op":"add","path":"/c/0","value":0}]`,
+			result: `{"a/b":{"m~n":[1]},"c":[0,1]}`,
+		},
+		{
+			doc:    `{"baz":"qux","foo":["a",2,"c"]}`,
+			patch:  `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2.0}]`,
+			result: `{"baz":"qux","foo":["a",2,"c"]}`,
+		},
+		{
+			doc:    `{"foo":1}`,
+			patch:  `[{"op":"replace","path":"","value":[1]}]`,
+			result: `[1]`,
+		},
+		{
+			doc:    `{"id":1,"html":"<b>"}`,
+			patch:  `[{"op":"replace","path":"/id","value":"<ignore-diff>"}]`,
+			result: `{"html":"<b>","id":"<ignore-diff>"}`,
+		},
+		{
+			doc:   `{"baz":"<b>"}`,
+			patch: `[{"op":"test","path":"/baz","value":"<i>"}]`,
+			err:   `operation 0 (test "/baz") failed: test failed: expected "<i>", got "<b>"`,
+		},
+		{
+			doc:   `{"baz":"qux"}`,
+			patch: `[{"op":"test","path":"/baz","value":"bar"}]`,
+			err:   `operation 0 (test "/baz") failed: test failed: expected "bar", got "qux"`,
+		},
+		{
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"test","path":"/foo","value":"bar"},{"op":"add","path":"/baz/bat","value":"qux"}]`,
+			err:   `operation 1 (add "/baz/bat") failed: path not found: /baz`,
+		},
+		{
+			doc:   `{"foo":[1,2]}`,
+			patch: `[{"op":"remove","path":"/foo/2"}]`,
+			err:   `operation 0 (remove "/foo/2") failed: path not found: array index out of bounds /foo/2`,
+		},
+		{
+			doc:   `{"foo":[1,2]}`,
+			patch: `[{"op":"add","path":"/foo/01","value":3}]`,
+			err:   `operation 0 (add "/foo/01") failed: path not found: invalid array index /foo/01`,
+		},
+		{
+			doc:   `{"foo":{"bar":1}}`,
+			patch: `[{"op":"move","from":"/foo","path":"/foo/bar/baz"}]`,
+			err:   `operation 0 (move "/foo/bar/baz") failed: invalid patch: can not move /foo into its child`,
+		},
+		{
+			doc:   `{"foo":1}`,
+			patch: `[{"op":"add","path":"foo","value":1}]`,
+			err:   `operation 0 (add "foo") failed: invalid patch: JSON Pointer must start with /: foo`,
+		},
+		{
+			doc:   `{"foo":1}`,
+			patch: `[{"op":"add","path":"/bar"}]`,
+			err:   `operation 0 (add "/bar") failed: invalid patch: missing value`,
+		},
+	} {
+		t.Run(tc.patch, func(t *testing.T) {
+			res, err := diff.ApplyJSONPatch([]byte(tc.doc), []byte(tc.patch))
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+
+				var pe *diff.PatchError
+				assert.True(t, errors.As(err, &pe))
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.result, string(res))
+		})
+	}
+}
+
+func TestApplyJSONPatch_testFailed(t *testing.T) {
+	_, err := diff.ApplyJSONPatch([]byte(`{"a":[1]}`), []byte(`[{"op":"test","path":"/a","value":[2]}]`))
+	assert.True(t, errors.Is(err, diff.ErrTestFailed))
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	for _, tc := range []struct {
+		doc, patch, result string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+		{`{"id":1,"b":"&"}`, `{"id":"<ignore-diff>"}`, `{"b":"&","id":"<ignore-diff>"}`},
+	} {
+		t.Run(tc.patch, func(t *testing.T) {
+			res, err := diff.ApplyMergePatch([]byte(tc.doc), []byte(tc.patch))
+			require.NoError(t, err)
+			assert.Equal(t, tc.result, string(res))
+		})
+	}
+}
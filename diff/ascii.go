@@ -5,6 +5,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
+
+	dmp "github.com/sergi/go-diff/diffmatchpatch"
 )
 
 // NewASCIIFormatter creates a new ASCIIFormatter instance with the specified left data and configuration settings.
@@ -17,49 +20,89 @@ func NewASCIIFormatter(left interface{}, config ASCIIFormatterConfig) *ASCIIForm
 
 // ASCIIFormatter is used to generate ASCII representations of differences between JSON-like data structures.
 type ASCIIFormatter struct {
-	left    interface{}
-	config  ASCIIFormatterConfig
-	buffer  *bytes.Buffer
-	path    []string
-	size    []int
-	inArray []bool
-	line    *ASCIILine
+	left     interface{}
+	config   ASCIIFormatterConfig
+	buffer   *bytes.Buffer
+	path     []string
+	leftPath []string // Names of values in left data, names of moved items differ from path.
+	size     []int
+	inArray  []bool
+	line     *ASCIILine
 }
 
 // ASCIIFormatterConfig specifies configuration options for formatting ASCII representations of data structures.
 // ShowArrayIndex determines if array indices should be displayed in the formatted output.
 // Coloring enables or disables colored output in the formatted result.
+// Annotations are comments by JSON Pointer of left value, they are appended to deleted lines and
+// to opening lines of changed objects and arrays, e.g. `-  "b": 1 // $varB`.
 type ASCIIFormatterConfig struct {
 	ShowArrayIndex bool
 	Coloring       bool
+	Annotations    map[string]string
 }
 
 // ASCIILine represents a line in an ASCII-formatted output with a marker, indentation, and a buffer containing content.
 type ASCIILine struct {
-	marker string
-	indent int
-	buffer *bytes.Buffer
+	marker  string
+	indent  int
+	buffer  *bytes.Buffer
+	comment string
 }
 
 // Format formats the differences between two JSON objects into an ASCII representation using the provided Diff.
 func (f *ASCIIFormatter) Format(diff Diff) (result string, err error) {
 	f.buffer = bytes.NewBuffer([]byte{})
 	f.path = []string{}
+	f.leftPath = []string{}
 	f.size = []int{}
 	f.inArray = []bool{}
 
-	if v, ok := f.left.(map[string]interface{}); ok {
+	if rootChanged(diff.Deltas()) {
+		if err := f.formatValue(diff); err != nil {
+			return "", err
+		}
+	} else if v, ok := f.left.(map[string]interface{}); ok {
 		f.formatObject(v, diff)
 	} else if v, ok := f.left.([]interface{}); ok {
 		f.formatArray(v, diff)
-	} else {
-		return "", fmt.Errorf("expected map[string]interface{} or []interface{}, got %T",
-			f.left)
+	} else if err := f.formatValue(diff); err != nil {
+		return "", err
 	}
 
 	return f.buffer.String(), nil
 }
 
+// rootChanged checks if deltas describe replacement of the whole value.
+func rootChanged(deltas []Delta) bool {
+	for _, delta := range deltas {
+		if d, ok := delta.(PostDelta); ok && d.PostPosition() == (Root{}) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// formatValue formats scalar root value or root value replaced with a value of another type.
+func (f *ASCIIFormatter) formatValue(df Diff) error {
+	f.size = []int{0}
+	f.inArray = []bool{true}
+
+	if !df.Modified() {
+		f.printRecursive("", f.left, ASCIISame)
+
+		return nil
+	}
+
+	for _, delta := range df.Deltas() {
+		if err := f.processDelta(f.left, delta, "", ""); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (f *ASCIIFormatter) formatObject(left map[string]interface{}, df Diff) {
 	f.addLineWith("{")
 	f.push("ROOT", len(left), false)
@@ -84,27 +127,122 @@ func (f *ASCIIFormatter) formatArray(left []interface{}, df Diff) {
 	f.addLineWith("]")
 }
 
+// processArray prints array items following the order of deltas application.
 func (f *ASCIIFormatter) processArray(array []interface{}, deltas []Delta) error {
-	patchedIndex := 0
-
-	for index, value := range array {
-		if err := f.processItem(value, deltas, Index(index)); err != nil {
-			return err
+	for _, delta := range deltas {
+		switch delta.(type) {
+		case *Added, *Moved:
+			f.size[len(f.size)-1]++
 		}
+	}
+
+	movedFrom := make(map[Delta]int) // Left indexes of changed moved items by their changes.
 
-		patchedIndex++
+	for _, delta := range deltas {
+		if d, ok := delta.(*Moved); ok {
+			if changes, ok := d.Delta.(Delta); ok {
+				movedFrom[changes] = int(d.PrePosition().(Index))
+			}
+		}
 	}
 
-	// additional Added
+	return walkArray(array, deltas, func(marker string, index int, value interface{}, delta Delta) error {
+		if delta != nil {
+			leftName := Index(index).String()
+			if leftIndex, ok := movedFrom[delta]; ok {
+				leftName = Index(leftIndex).String()
+			}
+
+			return f.processDelta(value, delta, Index(index).String(), leftName)
+		}
+
+		f.printRecursive(Index(index).String(), value, marker)
+
+		return nil
+	})
+}
+
+// walkArray visits items of array in order of deltas application:
+// pre deltas refer to items of left array, post deltas refer to items of the array after deletions and insertions.
+//
+// Deleted and moved away items are visited with ASCIIDeleted marker and left index, added and moved in items
+// are visited with ASCIIAdded marker and resulting index, other items are visited with ASCIISame marker,
+// left index and a delta if item is changed. Moved in items with changes are visited with ASCIISame marker,
+// resulting index and a delta. Changes of items of unordered arrays are visited at left positions of items.
+func walkArray(
+	array []interface{},
+	deltas []Delta,
+	visit func(marker string, index int, value interface{}, delta Delta) error,
+) error {
+	deleted := make(map[int]interface{})  // Values of deleted or moved away items by left index.
+	inserted := make(map[int]interface{}) // Values of added or moved in items by resulting index.
+	changed := make(map[int]Delta)        // Deltas of resulting items.
+	changedLeft := make(map[int]Delta)    // Deltas of items of unordered arrays by left index.
+	movedChanged := make(map[int]Delta)   // Deltas of moved in items by resulting index.
+
 	for _, delta := range deltas {
-		if d, ok := delta.(*Added); ok {
-			// skip items already processed
-			if int(d.Position.(Index)) < len(array) {
-				continue
+		switch d := delta.(type) {
+		case *Deleted:
+			deleted[int(d.PrePosition().(Index))] = d.Value
+		case *Moved:
+			deleted[int(d.PrePosition().(Index))] = d.Value
+			inserted[int(d.PostPosition().(Index))] = d.Value
+
+			if delta, ok := d.Delta.(Delta); ok {
+				movedChanged[int(d.PostPosition().(Index))] = delta
 			}
+		case *Added:
+			inserted[int(d.PostPosition().(Index))] = d.Value
+		case PostDelta:
+			if from, ok := changedFrom(delta); ok {
+				changedLeft[int(from)] = delta
+			} else {
+				changed[int(d.PostPosition().(Index))] = delta
+			}
+		default:
+			return errors.New("unknown Delta type detected")
+		}
+	}
 
-			f.printRecursive(d.String(), d.Value, ASCIIAdded)
+	resultLen := len(array) - len(deleted) + len(inserted)
+
+	for leftIndex, resultIndex := 0, 0; leftIndex < len(array) || resultIndex < resultLen; {
+		if value, ok := deleted[leftIndex]; ok {
+			if err := visit(ASCIIDeleted, leftIndex, value, nil); err != nil {
+				return err
+			}
+
+			leftIndex++
+
+			continue
+		}
+
+		if value, ok := inserted[resultIndex]; ok || leftIndex >= len(array) {
+			marker, delta := ASCIIAdded, movedChanged[resultIndex]
+			if delta != nil {
+				marker = ASCIISame
+			}
+
+			if err := visit(marker, resultIndex, value, delta); err != nil {
+				return err
+			}
+
+			resultIndex++
+
+			continue
+		}
+
+		delta, ok := changedLeft[leftIndex]
+		if !ok {
+			delta = changed[resultIndex]
 		}
+
+		if err := visit(ASCIISame, leftIndex, array[leftIndex], delta); err != nil {
+			return err
+		}
+
+		leftIndex++
+		resultIndex++
 	}
 
 	return nil
@@ -134,87 +272,94 @@ func (f *ASCIIFormatter) processItem(value interface{}, deltas []Delta, position
 	matchedDeltas := f.searchDeltas(deltas, position)
 	positionStr := position.String()
 
-	if len(matchedDeltas) > 0 {
-		for _, matchedDelta := range matchedDeltas {
-			switch d := matchedDelta.(type) {
-			case *Object:
-				switch value.(type) {
-				case map[string]interface{}:
-					// ok
-				default:
-					return errors.New("type mismatch")
-				}
-
-				o := value.(map[string]interface{})
-
-				f.newLine(ASCIISame)
-				f.printKey(positionStr)
-				f.print("{")
-				f.closeLine()
-				f.push(positionStr, len(o), false)
-
-				if err := f.processObject(o, d.Deltas); err != nil {
-					return err
-				}
-
-				f.pop()
-				f.newLine(ASCIISame)
-				f.print("}")
-				f.printComma()
-				f.closeLine()
-
-			case *Array:
-				switch value.(type) {
-				case []interface{}:
-					// ok
-				default:
-					return errors.New("type mismatch")
-				}
-
-				a := value.([]interface{})
-
-				f.newLine(ASCIISame)
-				f.printKey(positionStr)
-				f.print("[")
-				f.closeLine()
-				f.push(positionStr, len(a), true)
-
-				if err := f.processArray(a, d.Deltas); err != nil {
-					return err
-				}
-
-				f.pop()
-				f.newLine(ASCIISame)
-				f.print("]")
-				f.printComma()
-				f.closeLine()
-
-			case *Added:
-				f.printRecursive(positionStr, d.Value, ASCIIAdded)
-
-				f.size[len(f.size)-1]++
-
-			case *Modified:
-				savedSize := f.size[len(f.size)-1]
-				f.printRecursive(positionStr, d.OldValue, ASCIIDeleted)
-				f.size[len(f.size)-1] = savedSize
-				f.printRecursive(positionStr, d.NewValue, ASCIIAdded)
-
-			case *TextDiff:
-				savedSize := f.size[len(f.size)-1]
-				f.printRecursive(positionStr, d.OldValue, ASCIIDeleted)
-				f.size[len(f.size)-1] = savedSize
-				f.printRecursive(positionStr, d.NewValue, ASCIIAdded)
-
-			case *Deleted:
-				f.printRecursive(positionStr, d.Value, ASCIIDeleted)
-
-			default:
-				return errors.New("unknown Delta type detected")
-			}
-		}
-	} else {
+	if len(matchedDeltas) == 0 {
 		f.printRecursive(positionStr, value, ASCIISame)
+
+		return nil
+	}
+
+	for _, matchedDelta := range matchedDeltas {
+		if err := f.processDelta(value, matchedDelta, positionStr, positionStr); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// processDelta prints changes of value, leftName is a name of value in left data that is used for annotations.
+func (f *ASCIIFormatter) processDelta(value interface{}, delta Delta, positionStr, leftName string) error {
+	switch d := delta.(type) {
+	case *Object:
+		o, ok := value.(map[string]interface{})
+		if !ok {
+			return errors.New("type mismatch")
+		}
+
+		f.newLine(ASCIISame)
+		f.printKey(positionStr)
+		f.print("{")
+		f.annotate(leftName)
+		f.closeLine()
+		f.push(positionStr, len(o), false)
+		f.leftPath[len(f.leftPath)-1] = leftName
+
+		if err := f.processObject(o, d.Deltas); err != nil {
+			return err
+		}
+
+		f.pop()
+		f.newLine(ASCIISame)
+		f.print("}")
+		f.printComma()
+		f.closeLine()
+
+	case *Array:
+		a, ok := value.([]interface{})
+		if !ok {
+			return errors.New("type mismatch")
+		}
+
+		f.newLine(ASCIISame)
+		f.printKey(positionStr)
+		f.print("[")
+		f.annotate(leftName)
+		f.closeLine()
+		f.push(positionStr, len(a), true)
+		f.leftPath[len(f.leftPath)-1] = leftName
+
+		if err := f.processArray(a, d.Deltas); err != nil {
+			return err
+		}
+
+		f.pop()
+		f.newLine(ASCIISame)
+		f.print("]")
+		f.printComma()
+		f.closeLine()
+
+	case *Added:
+		f.printRecursive(positionStr, d.Value, ASCIIAdded)
+
+		f.size[len(f.size)-1]++
+
+	case *Modified:
+		savedSize := f.size[len(f.size)-1]
+		f.printRecursive(positionStr, d.OldValue, ASCIIDeleted)
+		f.size[len(f.size)-1] = savedSize
+		f.printRecursive(positionStr, d.NewValue, ASCIIAdded)
+
+	case *TextDiff:
+		savedSize := f.size[len(f.size)-1]
+		f.printTextDiff(positionStr, d, ASCIIDeleted)
+		f.size[len(f.size)-1] = savedSize
+		f.printTextDiff(positionStr, d, ASCIIAdded)
+
+	case *Deleted:
+		f.printRecursive(positionStr, d.Value, ASCIIDeleted)
+
+	default:
+		return errors.New("unknown Delta type detected")
 	}
 
 	return nil
@@ -252,20 +397,32 @@ const (
 	ASCIIDeleted = "-"
 )
 
+// Enclosing of changed parts of long strings, used if coloring is disabled.
+const (
+	ASCIITextDeletedStart = "[-"
+	ASCIITextDeletedEnd   = "-]"
+	ASCIITextAddedStart   = "{+"
+	ASCIITextAddedEnd     = "+}"
+)
+
 // ACSIIStyles is a map defining ANSI color styles for different ASCII markers used in formatting output.
 var ACSIIStyles = map[string]string{
-	ASCIIAdded:   "30;42",
-	ASCIIDeleted: "30;41",
+	ASCIIAdded:            "30;42",
+	ASCIIDeleted:          "30;41",
+	ASCIITextAddedStart:   "30;102",
+	ASCIITextDeletedStart: "30;101",
 }
 
 func (f *ASCIIFormatter) push(name string, size int, array bool) {
 	f.path = append(f.path, name)
+	f.leftPath = append(f.leftPath, name)
 	f.size = append(f.size, size)
 	f.inArray = append(f.inArray, array)
 }
 
 func (f *ASCIIFormatter) pop() {
 	f.path = f.path[0 : len(f.path)-1]
+	f.leftPath = f.leftPath[0 : len(f.leftPath)-1]
 	f.size = f.size[0 : len(f.size)-1]
 	f.inArray = f.inArray[0 : len(f.inArray)-1]
 }
@@ -301,6 +458,10 @@ func (f *ASCIIFormatter) closeLine() {
 
 	f.buffer.Write(f.line.buffer.Bytes())
 
+	if f.line.comment != "" {
+		f.buffer.WriteString(" // " + f.line.comment)
+	}
+
 	if f.config.Coloring && ok {
 		f.buffer.WriteString("\x1b[0m")
 	}
@@ -309,6 +470,10 @@ func (f *ASCIIFormatter) closeLine() {
 }
 
 func (f *ASCIIFormatter) printKey(name string) {
+	if len(f.path) == 0 { // Root value has no key.
+		return
+	}
+
 	if !f.inArray[len(f.inArray)-1] {
 		fmt.Fprintf(f.line.buffer, `"%s": `, name)
 	} else if f.config.ShowArrayIndex {
@@ -338,6 +503,69 @@ func (f *ASCIIFormatter) printValue(value interface{}) {
 	}
 }
 
+// printTextDiff prints old or new string with highlighted changes.
+func (f *ASCIIFormatter) printTextDiff(name string, d *TextDiff, marker string) {
+	f.newLine(marker)
+	f.printKey(name)
+	f.print(`"` + f.textDiff(d, marker == ASCIIDeleted) + `"`)
+	f.printComma()
+
+	if marker == ASCIIDeleted {
+		f.annotate(name)
+	}
+
+	f.closeLine()
+}
+
+// textDiff renders old or new string of TextDiff with enclosed or colored changes.
+func (f *ASCIIFormatter) textDiff(d *TextDiff, old bool) string {
+	op, start, end := dmp.DiffInsert, ASCIITextAddedStart, ASCIITextAddedEnd
+	lineStyle := ACSIIStyles[ASCIIAdded]
+
+	if old {
+		op, start, end = dmp.DiffDelete, ASCIITextDeletedStart, ASCIITextDeletedEnd
+		lineStyle = ACSIIStyles[ASCIIDeleted]
+	}
+
+	if f.config.Coloring {
+		end = "\x1b[0m\x1b[" + lineStyle + "m"
+		start = "\x1b[" + ACSIIStyles[start] + "m"
+	}
+
+	var res strings.Builder
+
+	for _, df := range d.Diffs() {
+		switch df.Type {
+		case dmp.DiffEqual:
+			res.WriteString(df.Text)
+		case op:
+			res.WriteString(start + df.Text + end)
+		}
+	}
+
+	return res.String()
+}
+
+// annotate adds comment of configured annotation to current line of value with a name.
+func (f *ASCIIFormatter) annotate(name string) {
+	if len(f.config.Annotations) == 0 {
+		return
+	}
+
+	path := f.leftPath
+	if len(path) > 0 && path[0] == "ROOT" {
+		path = path[1:]
+	}
+
+	if name != "" {
+		path = append(path[:len(path):len(path)], name)
+	} else if len(f.path) > 0 { // Items of deleted arrays have no names.
+		return
+	}
+
+	f.line.comment = f.config.Annotations[Pointer(path...)]
+}
+
 func (f *ASCIIFormatter) print(a string) {
 	f.line.buffer.WriteString(a)
 }
@@ -348,6 +576,11 @@ func (f *ASCIIFormatter) printRecursive(name string, value interface{}, marker s
 		f.newLine(marker)
 		f.printKey(name)
 		f.print("{")
+
+		if marker == ASCIIDeleted {
+			f.annotate(name)
+		}
+
 		f.closeLine()
 
 		m := value
@@ -370,6 +603,11 @@ func (f *ASCIIFormatter) printRecursive(name string, value interface{}, marker s
 		f.newLine(marker)
 		f.printKey(name)
 		f.print("[")
+
+		if marker == ASCIIDeleted {
+			f.annotate(name)
+		}
+
 		f.closeLine()
 
 		s := value
@@ -392,6 +630,11 @@ func (f *ASCIIFormatter) printRecursive(name string, value interface{}, marker s
 		f.printKey(name)
 		f.printValue(value)
 		f.printComma()
+
+		if marker == ASCIIDeleted {
+			f.annotate(name)
+		}
+
 		f.closeLine()
 	}
 }
@@ -0,0 +1,53 @@
+package diff_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson/diff"
+)
+
+func TestASCIIFormatter_Format_orderedArray(t *testing.T) {
+	for _, tc := range []struct {
+		left, right, ascii string
+	}{
+		{
+			// Unchanged item stays between added items.
+			left:  `{"license":["MIT"]}`,
+			right: `{"license":["BSD3","MIT","Apache 2.0"]}`,
+			ascii: ` {
+   "license": [
++    "BSD3",
+     "MIT",
++    "Apache 2.0"
+   ]
+ }
+`,
+		},
+		{
+			left:  `[1,2,3,4]`,
+			right: `[1,3,5,4]`,
+			ascii: ` [
+   1,
+-  2,
+   3,
++  5,
+   4
+ ]
+`,
+		},
+	} {
+		var left, right interface{}
+
+		require.NoError(t, json.Unmarshal([]byte(tc.left), &left))
+		require.NoError(t, json.Unmarshal([]byte(tc.right), &right))
+
+		d := diff.New().CompareValues(left, right)
+
+		s, err := diff.NewASCIIFormatter(left, diff.ASCIIFormatterConfig{}).Format(d)
+		require.NoError(t, err)
+		assert.Equal(t, tc.ascii, s)
+	}
+}
@@ -0,0 +1,99 @@
+package diff
+
+// ChangeType is a kind of Change.
+type ChangeType string
+
+// Change types.
+const (
+	ChangeAdded    = ChangeType("added")
+	ChangeDeleted  = ChangeType("deleted")
+	ChangeModified = ChangeType("modified")
+	ChangeMoved    = ChangeType("moved")
+)
+
+// Change describes a difference at a single location.
+type Change struct {
+	// Type is a kind of change.
+	Type ChangeType
+
+	// Path is a JSON Pointer to changed value, for moved value it points to the new location.
+	Path string
+
+	// From is a JSON Pointer to original location of moved value.
+	From string
+
+	// OldValue is a value before change, nil for added value.
+	OldValue interface{}
+
+	// NewValue is a value after change, nil for deleted value.
+	NewValue interface{}
+}
+
+// Changes flattens nested deltas into a list of changes.
+//
+// Positions of array items follow deltas semantics: deleted items are indexed in the left array,
+// other items are indexed in the resulting array. Moved item with changes is reported as moved,
+// followed by its changes at the new location.
+func Changes(deltas []Delta) []Change {
+	return appendChanges(nil, deltas, nil)
+}
+
+func appendChanges(changes []Change, deltas []Delta, path []string) []Change {
+	for _, delta := range deltas {
+		switch d := delta.(type) {
+		case *Object:
+			changes = appendChanges(changes, d.Deltas, childPath(path, d.PostPosition()))
+		case *Array:
+			changes = appendChanges(changes, d.Deltas, childPath(path, d.PostPosition()))
+		case *Added:
+			changes = append(changes, Change{
+				Type:     ChangeAdded,
+				Path:     Pointer(childPath(path, d.PostPosition())...),
+				NewValue: d.Value,
+			})
+		case *Deleted:
+			changes = append(changes, Change{
+				Type:     ChangeDeleted,
+				Path:     Pointer(childPath(path, d.PrePosition())...),
+				OldValue: d.Value,
+			})
+		case *Modified:
+			changes = append(changes, Change{
+				Type:     ChangeModified,
+				Path:     Pointer(childPath(path, d.PostPosition())...),
+				OldValue: d.OldValue,
+				NewValue: d.NewValue,
+			})
+		case *TextDiff:
+			changes = append(changes, Change{
+				Type:     ChangeModified,
+				Path:     Pointer(childPath(path, d.PostPosition())...),
+				OldValue: d.OldValue,
+				NewValue: d.NewValue,
+			})
+		case *Moved:
+			changes = append(changes, Change{
+				Type:     ChangeMoved,
+				Path:     Pointer(childPath(path, d.PostPosition())...),
+				From:     Pointer(childPath(path, d.PrePosition())...),
+				OldValue: d.Value,
+				NewValue: d.Value,
+			})
+
+			if delta, ok := d.Delta.(Delta); ok {
+				changes = appendChanges(changes, []Delta{delta}, path)
+			}
+		}
+	}
+
+	return changes
+}
+
+// childPath returns path of a value at position within parent path.
+func childPath(path []string, position Position) []string {
+	if _, ok := position.(Root); ok {
+		return path
+	}
+
+	return append(path[:len(path):len(path)], position.String())
+}
@@ -0,0 +1,41 @@
+package diff_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson/diff"
+)
+
+func TestChanges(t *testing.T) {
+	var left, right interface{}
+
+	require.NoError(t, json.Unmarshal([]byte(`{"a":1,"b":"x","items":[{"id":1,"v":1},{"id":2},{"id":3}]}`), &left))
+	require.NoError(t, json.Unmarshal([]byte(`{"a":2,"c":true,"items":[{"id":2},{"id":3},{"id":1,"v":2}]}`), &right))
+
+	d := diff.NewWithConfig(diff.DifferConfig{ArrayKeys: map[string]string{"/items": "id"}}).CompareValues(left, right)
+
+	// Moved item with changes is reported as moved and then modified at the new location.
+	assert.Equal(t, []diff.Change{
+		{Type: diff.ChangeModified, Path: "/a", OldValue: 1.0, NewValue: 2.0},
+		{Type: diff.ChangeDeleted, Path: "/b", OldValue: "x"},
+		{
+			Type: diff.ChangeMoved, Path: "/items/2", From: "/items/0",
+			OldValue: map[string]interface{}{"id": 1.0, "v": 1.0}, NewValue: map[string]interface{}{"id": 1.0, "v": 1.0},
+		},
+		{Type: diff.ChangeModified, Path: "/items/2/v", OldValue: 1.0, NewValue: 2.0},
+		{Type: diff.ChangeAdded, Path: "/c", NewValue: true},
+	}, diff.Changes(d.Deltas()))
+
+	d = diff.NewWithConfig(diff.DifferConfig{UnorderedArrays: true}).CompareValues(left, right)
+
+	// Changed item of unordered array is reported at its actual location.
+	assert.Equal(t, []diff.Change{
+		{Type: diff.ChangeModified, Path: "/a", OldValue: 1.0, NewValue: 2.0},
+		{Type: diff.ChangeDeleted, Path: "/b", OldValue: "x"},
+		{Type: diff.ChangeModified, Path: "/items/2/v", OldValue: 1.0, NewValue: 2.0},
+		{Type: diff.ChangeAdded, Path: "/c", NewValue: true},
+	}, diff.Changes(d.Deltas()))
+}
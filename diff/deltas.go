@@ -77,6 +77,20 @@ func (i Index) CompareTo(another Position) bool {
 	return i < another.(Index)
 }
 
+// Root is a Position of a whole document, it is used for difference of scalar documents
+// or documents of different types.
+type Root struct{}
+
+// String returns empty string as root has no name.
+func (Root) String() (name string) {
+	return ""
+}
+
+// CompareTo returns false as there is only one root.
+func (Root) CompareTo(_ Position) bool {
+	return false
+}
+
 // A PreDelta is a Delta that has a position of the left side JSON object.
 // Deltas implements this interface should be applies before PostDeltas.
 type PreDelta interface {
@@ -120,12 +134,46 @@ type PostDelta interface {
 	PostApply(object interface{}) interface{}
 }
 
-type postDelta struct{ Position }
+type postDelta struct {
+	Position
+
+	// leftPosition is a position of changed item in left array, if it is not the same as the position
+	// in resulting array, e.g. for items of unordered arrays.
+	leftPosition Position
+}
 
 func (i postDelta) PostPosition() Position {
 	return i.Position
 }
 
+func (i postDelta) changedFrom() Position {
+	return i.leftPosition
+}
+
+// changedFrom returns position of an item in left array that is changed by delta at another position
+// in resulting array, ok is false if the delta changes item at its resulting position.
+func changedFrom(delta Delta) (position Index, ok bool) {
+	if d, isPost := delta.(interface{ changedFrom() Position }); isPost {
+		position, ok = d.changedFrom().(Index)
+	}
+
+	return position, ok
+}
+
+// setChangedFrom sets position of an item in left array that is changed by delta.
+func setChangedFrom(delta Delta, position Position) {
+	switch d := delta.(type) {
+	case *Object:
+		d.leftPosition = position
+	case *Array:
+		d.leftPosition = position
+	case *Modified:
+		d.leftPosition = position
+	case *TextDiff:
+		d.leftPosition = position
+	}
+}
+
 type postDeltas []PostDelta
 
 // Len returns the number of elements in the postDeltas slice. It is used for implementing the sort.Interface.
@@ -154,7 +202,7 @@ type Object struct {
 
 // NewObject returns an Object.
 func NewObject(position Position, deltas []Delta) *Object {
-	d := Object{postDelta: postDelta{position}, Deltas: deltas}
+	d := Object{postDelta: postDelta{Position: position}, Deltas: deltas}
 	d.similarityCache = newSimilarityCache(&d)
 
 	return &d
@@ -191,7 +239,7 @@ type Array struct {
 
 // NewArray returns an Array.
 func NewArray(position Position, deltas []Delta) *Array {
-	d := Array{postDelta: postDelta{position}, Deltas: deltas}
+	d := Array{postDelta: postDelta{Position: position}, Deltas: deltas}
 	d.similarityCache = newSimilarityCache(&d)
 
 	return &d
@@ -228,7 +276,7 @@ type Added struct {
 
 // NewAdded returns a new Added.
 func NewAdded(position Position, value interface{}) *Added {
-	d := Added{postDelta: postDelta{position}, Value: value}
+	d := Added{postDelta: postDelta{Position: position}, Value: value}
 
 	return &d
 }
@@ -274,7 +322,7 @@ type Modified struct {
 // NewModified returns a Modified.
 func NewModified(position Position, oldValue, newValue interface{}) *Modified {
 	d := Modified{
-		postDelta: postDelta{position},
+		postDelta: postDelta{Position: position},
 		OldValue:  oldValue,
 		NewValue:  newValue,
 	}
@@ -376,6 +424,16 @@ func (d *TextDiff) DiffString() string {
 	return dm.PatchToText(d.Diff)
 }
 
+// Diffs returns character level differences between old and new strings.
+func (d *TextDiff) Diffs() []dmp.Diff {
+	oldValue, _ := d.OldValue.(string)
+	newValue, _ := d.NewValue.(string)
+
+	dm := dmp.New()
+
+	return dm.DiffCleanupSemantic(dm.DiffMain(oldValue, newValue, false))
+}
+
 // Deleted represents a change where an element is removed from a map or slice at a specific position.
 // It embeds preDelta to store positional metadata and includes the Value field to reference the deleted element.
 type Deleted struct {
@@ -433,7 +491,7 @@ type Moved struct {
 func NewMoved(oldPosition Position, newPosition Position, value interface{}, delta Delta) *Moved {
 	d := Moved{
 		preDelta:  preDelta{oldPosition},
-		postDelta: postDelta{newPosition},
+		postDelta: postDelta{Position: newPosition},
 		Value:     value,
 		Delta:     delta,
 	}
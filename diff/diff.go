@@ -8,6 +8,7 @@ package diff
 import (
 	"container/list"
 	"encoding/json"
+	"math/big"
 	"reflect"
 	"sort"
 
@@ -39,13 +40,71 @@ func (diff *diff) Modified() bool {
 // A Differ compares JSON objects and apply patches.
 type Differ struct {
 	textDiffMinimumLength int
+	config                DifferConfig
+	unorderedPaths        []Selector
+	arrayKeys             []arrayKey
+	path                  []string
+}
+
+// DifferConfig specifies configuration options for comparison of JSON values.
+type DifferConfig struct {
+	// SemanticNumbers enables comparison of numbers by their decoded values instead of textual form,
+	// e.g. 1.0 is equal to 1 and 1e2 is equal to 100. Decoded values have arbitrary precision.
+	SemanticNumbers bool
+
+	// NumberTolerance is a maximum absolute difference of equal numbers, implies SemanticNumbers.
+	NumberTolerance float64
+
+	// NumberRelativeTolerance is a maximum difference of equal numbers relative to the larger magnitude,
+	// implies SemanticNumbers.
+	NumberRelativeTolerance float64
+
+	// UnorderedArrays enables order-insensitive comparison of all arrays,
+	// array items are matched as a multiset and only missing, extra or changed items are reported.
+	// Positions of deltas refer to actual items.
+	UnorderedArrays bool
+
+	// UnorderedArrayPaths enables order-insensitive comparison of arrays at locations,
+	// see ParseSelector for syntax.
+	UnorderedArrayPaths []string
+
+	// TextDiffMinimumLength is a minimum length of string to describe its change with TextDiff, default 30.
+	// Negative value disables TextDiff.
+	TextDiffMinimumLength int
+
+	// ArrayKeys maps locations of arrays of objects to identity properties, e.g. {"/users": "id"}, see ParseSelector
+	// for syntax, locations of items, e.g. "/users/*", are also accepted. Items of such arrays are paired by value of identity property instead of similarity, so that
+	// changed, moved, missing and extra items are reported by identity.
+	//
+	// Arrays with items that are not objects or have missing or duplicate identities are compared by similarity.
+	ArrayKeys map[string]string
 }
 
 // New returns new Differ with default configuration.
 func New() *Differ {
-	return &Differ{
+	return NewWithConfig(DifferConfig{})
+}
+
+// NewWithConfig returns new Differ with custom configuration.
+func NewWithConfig(config DifferConfig) *Differ {
+	differ := &Differ{
 		textDiffMinimumLength: 30,
+		config:                config,
+	}
+
+	if config.TextDiffMinimumLength != 0 {
+		differ.textDiffMinimumLength = config.TextDiffMinimumLength
+	}
+
+	for _, p := range config.UnorderedArrayPaths {
+		differ.unorderedPaths = append(differ.unorderedPaths, ParseSelector(p))
+	}
+
+	for _, p := range sortedStringKeys(config.ArrayKeys) {
+		differ.arrayKeys = append(differ.arrayKeys, arrayKey{selector: ParseSelector(p), key: config.ArrayKeys[p]})
 	}
+
+	return differ
 }
 
 // Compare compares two JSON strings as []bytes and return a Diff object.
@@ -68,6 +127,28 @@ func (differ *Differ) Compare(
 	return differ.CompareObjects(leftMap, rightMap), nil
 }
 
+// CompareValues compares two JSON values of any type and return a Diff object.
+//
+// Difference of scalar values or values of different types is described by a Modified with Root position.
+func (differ *Differ) CompareValues(left, right interface{}) Diff {
+	switch l := left.(type) {
+	case map[string]interface{}:
+		if r, ok := right.(map[string]interface{}); ok {
+			return differ.CompareObjects(l, r)
+		}
+	case []interface{}:
+		if r, ok := right.([]interface{}); ok {
+			return differ.CompareArrays(l, r)
+		}
+	}
+
+	if same, delta := differ.compareValues(Root{}, left, right); !same {
+		return &diff{deltas: []Delta{delta}}
+	}
+
+	return &diff{deltas: []Delta{}}
+}
+
 // CompareObjects compares two JSON object as map[string]interface{}
 // and return a Diff object.
 func (differ *Differ) CompareObjects(
@@ -133,6 +214,16 @@ func (differ *Differ) compareArrays(
 	left []interface{},
 	right []interface{},
 ) (deltas []Delta) {
+	if key, ok := differ.arrayKey(); ok {
+		if deltas, ok := differ.compareArraysByKey(left, right, key); ok {
+			return deltas
+		}
+	}
+
+	if differ.unordered() {
+		return differ.compareArraysUnordered(left, right)
+	}
+
 	deltas = make([]Delta, 0)
 	// LCS index pairs
 	lcsPairs := lcs.New(left, right).IndexPairs()
@@ -251,6 +342,20 @@ func (differ *Differ) compareArrays(
 	return deltas
 }
 
+func (differ *Differ) unordered() bool {
+	if differ.config.UnorderedArrays {
+		return true
+	}
+
+	for _, s := range differ.unorderedPaths {
+		if s.Match(differ.path) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (differ *Differ) compareValues(
 	position Position,
 	left interface{},
@@ -262,20 +367,25 @@ func (differ *Differ) compareValues(
 
 	switch l := left.(type) {
 	case map[string]interface{}:
+		differ.push(position)
 		childDeltas := differ.compareMaps(l, right.(map[string]interface{}))
+		differ.pop(position)
+
 		if len(childDeltas) > 0 {
 			return false, NewObject(position, childDeltas)
 		}
 
 	case []interface{}:
+		differ.push(position)
 		childDeltas := differ.compareArrays(l, right.([]interface{}))
+		differ.pop(position)
 
 		if len(childDeltas) > 0 {
 			return false, NewArray(position, childDeltas)
 		}
 
 	default:
-		if !reflect.DeepEqual(left, right) {
+		if !differ.scalarsEqual(left, right) {
 			if ln, ok := left.(json.Number); ok {
 				left = shared.DecodeJSONNumber(ln)
 			}
@@ -286,6 +396,7 @@ func (differ *Differ) compareValues(
 
 			if reflect.ValueOf(left).Kind() == reflect.String &&
 				reflect.ValueOf(right).Kind() == reflect.String &&
+				differ.textDiffMinimumLength >= 0 &&
 				differ.textDiffMinimumLength <= len(reflect.ValueOf(left).String()) {
 				textDiff := dmp.New()
 				patches := textDiff.PatchMake(reflect.ValueOf(left).String(), reflect.ValueOf(right).String())
@@ -300,6 +411,79 @@ func (differ *Differ) compareValues(
 	return true, nil
 }
 
+func (differ *Differ) push(position Position) {
+	if _, ok := position.(Root); !ok {
+		differ.path = append(differ.path, position.String())
+	}
+}
+
+func (differ *Differ) pop(position Position) {
+	if _, ok := position.(Root); !ok {
+		differ.path = differ.path[:len(differ.path)-1]
+	}
+}
+
+func (differ *Differ) scalarsEqual(left, right interface{}) bool {
+	c := differ.config
+	if !c.SemanticNumbers && c.NumberTolerance == 0 && c.NumberRelativeTolerance == 0 {
+		return reflect.DeepEqual(left, right)
+	}
+
+	l, ok := numberValue(left)
+	if !ok {
+		return reflect.DeepEqual(left, right)
+	}
+
+	r, ok := numberValue(right)
+	if !ok {
+		return false
+	}
+
+	if l.Cmp(r) == 0 {
+		return true
+	}
+
+	d := new(big.Rat).Sub(l, r)
+	d.Abs(d)
+
+	if c.NumberTolerance > 0 && d.Cmp(new(big.Rat).SetFloat64(c.NumberTolerance)) <= 0 {
+		return true
+	}
+
+	if c.NumberRelativeTolerance > 0 {
+		m := new(big.Rat).Abs(l)
+		if ra := new(big.Rat).Abs(r); ra.Cmp(m) > 0 {
+			m = ra
+		}
+
+		m.Mul(m, new(big.Rat).SetFloat64(c.NumberRelativeTolerance))
+
+		return d.Cmp(m) <= 0
+	}
+
+	return false
+}
+
+// numberValue decodes JSON number with arbitrary precision.
+func numberValue(v interface{}) (*big.Rat, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		return new(big.Rat).SetString(n.String())
+	case float64:
+		if r := new(big.Rat).SetFloat64(n); r != nil {
+			return r, true
+		}
+	case int64:
+		return new(big.Rat).SetInt64(n), true
+	case int:
+		return new(big.Rat).SetInt64(int64(n)), true
+	case uint64:
+		return new(big.Rat).SetUint64(n), true
+	}
+
+	return nil, false
+}
+
 func applyDeltas(deltas []Delta, object interface{}) interface{} {
 	preDeltas := make(preDeltas, 0)
 
@@ -360,7 +544,12 @@ func (differ *Differ) maximizeSimilarities(left []maybe, right []maybe) (resultD
 		for y := sizeY - 2; y >= 0; y-- {
 			prevX := dpTable[x+1][y]
 			prevY := dpTable[x][y+1]
-			score := deltaTable[x][y].Similarity() + dpTable[x+1][y+1]
+			score := 1.0 // values are equal, but not identical, e.g. numbers within tolerance
+			if deltaTable[x][y] != nil {
+				score = deltaTable[x][y].Similarity()
+			}
+
+			score += dpTable[x+1][y+1]
 
 			dpTable[x][y] = maxFloat(prevX, prevY, score)
 		}
@@ -397,18 +586,21 @@ func (differ *Differ) maximizeSimilarities(left []maybe, right []maybe) (resultD
 			freeRight = append(freeRight, right[y])
 			y++
 		default:
-			resultDeltas = append(resultDeltas, deltaTable[x][y])
+			if deltaTable[x][y] != nil {
+				resultDeltas = append(resultDeltas, deltaTable[x][y])
+			}
+
 			x++
 			y++
 		}
 	}
 
 	for ; x < sizeX-1; x++ {
-		freeLeft = append(freeLeft, left[x-1])
+		freeLeft = append(freeLeft, left[x])
 	}
 
 	for ; y < sizeY-1; y++ {
-		freeRight = append(freeRight, right[y-1])
+		freeRight = append(freeRight, right[y])
 	}
 
 	return resultDeltas, freeLeft, freeRight
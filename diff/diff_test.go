@@ -0,0 +1,52 @@
+package diff_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson/diff"
+)
+
+func TestDiffer_CompareValues_trailingItems(t *testing.T) {
+	for _, tc := range []struct {
+		left, right, summary string
+	}{
+		{
+			left:    `["a","b"]`,
+			right:   `["x","a","y","z"]`,
+			summary: "/0: added \"x\"\n/3: \"b\" -> \"z\"\n/2: added \"y\"\n",
+		},
+		{
+			left:    `[1,2,3]`,
+			right:   `[4,1,5,6,7]`,
+			summary: "/0: added 4\n/2: 2 -> 5\n/3: 3 -> 6\n/4: added 7\n",
+		},
+		{
+			left:  `[{"a":1},{"b":2},{"c":3},{"d":4}]`,
+			right: `[{"a":1,"x":1},{"b":2,"x":1}]`,
+			summary: "/0: removed {\"a\":1}\n/1: removed {\"b\":2}\n/2: removed {\"c\":3}\n/3: removed {\"d\":4}\n" +
+				"/0: added {\"a\":1,\"x\":1}\n/1: added {\"b\":2,\"x\":1}\n",
+		},
+	} {
+		var left, right interface{}
+
+		require.NoError(t, json.Unmarshal([]byte(tc.left), &left))
+		require.NoError(t, json.Unmarshal([]byte(tc.right), &right))
+
+		d := diff.New().CompareValues(left, right)
+
+		s, err := diff.NewPathSummaryFormatter(diff.PathSummaryFormatterConfig{}).Format(d)
+		require.NoError(t, err)
+		assert.Equal(t, tc.summary, s)
+
+		// Unmatched trailing items are taken by their own indexes, so that patch restores right array.
+		p, err := diff.NewJSONPatchFormatter(left).Format(d)
+		require.NoError(t, err)
+
+		patched, err := diff.ApplyJSONPatch([]byte(tc.left), []byte(p))
+		require.NoError(t, err)
+		assert.JSONEq(t, tc.right, string(patched))
+	}
+}
@@ -0,0 +1,305 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	dmp "github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// NewHTMLFormatter creates a new HTMLFormatter instance with the specified left data.
+func NewHTMLFormatter(left interface{}) *HTMLFormatter {
+	return &HTMLFormatter{
+		left: left,
+	}
+}
+
+// HTMLFormatter renders differences as a self-contained HTML fragment.
+//
+// Documents are rendered as a collapsible tree, containers with changes are expanded,
+// unchanged containers are collapsed.
+type HTMLFormatter struct {
+	left   interface{}
+	buffer *bytes.Buffer
+}
+
+var htmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// HTMLStyles is a map defining inline CSS styles of elements of HTML diff.
+var HTMLStyles = map[string]string{
+	"root":       "font-family:monospace;white-space:pre;line-height:1.4",
+	"children":   "padding-left:2ch",
+	"summary":    "cursor:pointer",
+	ASCIIAdded:   "background:#e6ffed;color:#22863a",
+	ASCIIDeleted: "background:#ffeef0;color:#b31d28",
+	"ins":        "background:#acf2bd;text-decoration:none",
+	"del":        "background:#fdb8c0;text-decoration:none",
+}
+
+// Format renders the differences between left data and the provided Diff as HTML.
+func (f *HTMLFormatter) Format(diff Diff) (result string, err error) {
+	f.buffer = bytes.NewBuffer([]byte{})
+
+	f.buffer.WriteString(`<div class="jsondiff" style="` + HTMLStyles["root"] + `">`)
+
+	switch {
+	case !diff.Modified():
+		f.printRecursive("", f.left, ASCIISame)
+	case rootChanged(diff.Deltas()):
+		for _, delta := range diff.Deltas() {
+			if err := f.processDelta("", f.left, delta); err != nil {
+				return "", err
+			}
+		}
+	default:
+		if err := f.processDelta("", f.left, f.rootDelta(diff.Deltas())); err != nil {
+			return "", err
+		}
+	}
+
+	f.buffer.WriteString("</div>")
+
+	return f.buffer.String(), nil
+}
+
+// rootDelta wraps root deltas into a container delta.
+func (f *HTMLFormatter) rootDelta(deltas []Delta) Delta {
+	if _, ok := f.left.([]interface{}); ok {
+		return NewArray(Root{}, deltas)
+	}
+
+	return NewObject(Root{}, deltas)
+}
+
+func (f *HTMLFormatter) processObject(object map[string]interface{}, deltas []Delta) error {
+	for _, name := range sortedKeys(object) {
+		matched := false
+
+		for _, delta := range deltas {
+			if d, ok := delta.(PostDelta); ok && d.PostPosition() == Name(name) ||
+				!ok && delta.(PreDelta).PrePosition() == Name(name) {
+				matched = true
+
+				if err := f.processDelta(name, object[name], delta); err != nil {
+					return err
+				}
+			}
+		}
+
+		if !matched {
+			f.printRecursive(name, object[name], ASCIISame)
+		}
+	}
+
+	for _, delta := range deltas {
+		if d, ok := delta.(*Added); ok {
+			f.printRecursive(d.String(), d.Value, ASCIIAdded)
+		}
+	}
+
+	return nil
+}
+
+func (f *HTMLFormatter) processDelta(key string, value interface{}, delta Delta) error {
+	switch d := delta.(type) {
+	case *Object:
+		o, ok := value.(map[string]interface{})
+		if !ok {
+			return errors.New("type mismatch")
+		}
+
+		f.openContainer(key, "{", ASCIISame, true)
+
+		if err := f.processObject(o, d.Deltas); err != nil {
+			return err
+		}
+
+		f.closeContainer("}", ASCIISame)
+
+	case *Array:
+		a, ok := value.([]interface{})
+		if !ok {
+			return errors.New("type mismatch")
+		}
+
+		f.openContainer(key, "[", ASCIISame, true)
+
+		err := walkArray(a, d.Deltas, func(marker string, index int, value interface{}, delta Delta) error {
+			if delta != nil {
+				return f.processDelta("", value, delta)
+			}
+
+			f.printRecursive("", value, marker)
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		f.closeContainer("]", ASCIISame)
+
+	case *Added:
+		f.printRecursive(key, d.Value, ASCIIAdded)
+
+	case *Deleted:
+		f.printRecursive(key, d.Value, ASCIIDeleted)
+
+	case *Modified:
+		f.printRecursive(key, d.OldValue, ASCIIDeleted)
+		f.printRecursive(key, d.NewValue, ASCIIAdded)
+
+	case *TextDiff:
+		f.printTextDiff(key, d)
+
+	default:
+		return errors.New("unknown Delta type detected")
+	}
+
+	return nil
+}
+
+// printTextDiff prints old and new strings with highlighted changed characters.
+func (f *HTMLFormatter) printTextDiff(key string, d *TextDiff) {
+	diffs := d.Diffs()
+
+	for _, marker := range []string{ASCIIDeleted, ASCIIAdded} {
+		f.openLine(marker)
+		f.printKey(key)
+		f.buffer.WriteString(`"`)
+
+		for _, df := range diffs {
+			text := htmlEscaper.Replace(escapeString(df.Text))
+
+			switch {
+			case df.Type == dmp.DiffEqual:
+				f.buffer.WriteString(text)
+			case df.Type == dmp.DiffDelete && marker == ASCIIDeleted:
+				f.buffer.WriteString(`<del style="` + HTMLStyles["del"] + `">` + text + `</del>`)
+			case df.Type == dmp.DiffInsert && marker == ASCIIAdded:
+				f.buffer.WriteString(`<ins style="` + HTMLStyles["ins"] + `">` + text + `</ins>`)
+			}
+		}
+
+		f.buffer.WriteString(`"`)
+		f.closeLine()
+	}
+}
+
+func (f *HTMLFormatter) printRecursive(key string, value interface{}, marker string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		f.openContainer(key, "{", marker, marker != ASCIISame)
+
+		for _, k := range sortedKeys(v) {
+			f.printRecursive(k, v[k], marker)
+		}
+
+		f.closeContainer("}", marker)
+
+	case []interface{}:
+		f.openContainer(key, "[", marker, marker != ASCIISame)
+
+		for _, item := range v {
+			f.printRecursive("", item, marker)
+		}
+
+		f.closeContainer("]", marker)
+
+	default:
+		f.openLine(marker)
+		f.printKey(key)
+		f.printValue(value)
+		f.closeLine()
+	}
+}
+
+func (f *HTMLFormatter) openContainer(key, bracket, marker string, open bool) {
+	if open {
+		f.buffer.WriteString("<details open>")
+	} else {
+		f.buffer.WriteString("<details>")
+	}
+
+	style := HTMLStyles["summary"]
+	if s := HTMLStyles[marker]; s != "" {
+		style += ";" + s
+	}
+
+	f.buffer.WriteString(`<summary style="` + style + `">` + htmlEscaper.Replace(marker) + " ")
+	f.printKey(key)
+	f.buffer.WriteString(bracket + "</summary>")
+	f.buffer.WriteString(`<div style="` + HTMLStyles["children"] + `">`)
+}
+
+func (f *HTMLFormatter) closeContainer(bracket, marker string) {
+	f.buffer.WriteString("</div>")
+	f.openLine(marker)
+	f.buffer.WriteString(bracket)
+	f.closeLine()
+	f.buffer.WriteString("</details>")
+}
+
+func (f *HTMLFormatter) openLine(marker string) {
+	class := "same"
+
+	switch marker {
+	case ASCIIAdded:
+		class = "added"
+	case ASCIIDeleted:
+		class = "deleted"
+	}
+
+	f.buffer.WriteString(`<div class="` + class + `"`)
+
+	if style := HTMLStyles[marker]; style != "" {
+		f.buffer.WriteString(` style="` + style + `"`)
+	}
+
+	f.buffer.WriteString(">" + htmlEscaper.Replace(marker) + " ")
+}
+
+func (f *HTMLFormatter) closeLine() {
+	f.buffer.WriteString("</div>")
+}
+
+func (f *HTMLFormatter) printKey(key string) {
+	if key != "" {
+		f.buffer.WriteString(htmlEscaper.Replace(`"` + escapeString(key) + `": `))
+	}
+}
+
+func (f *HTMLFormatter) printValue(value interface{}) {
+	var s string
+
+	switch v := value.(type) {
+	case string:
+		s = `"` + escapeString(v) + `"`
+	case nil:
+		s = "null"
+	case json.Number:
+		s = v.String()
+	default:
+		s = fmt.Sprint(v)
+	}
+
+	f.buffer.WriteString(htmlEscaper.Replace(s))
+}
+
+// escapeString escapes string as JSON without quotes.
+func escapeString(s string) string {
+	b := bytes.NewBuffer(nil)
+	enc := json.NewEncoder(b)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(s); err != nil {
+		return s
+	}
+
+	j := bytes.TrimSpace(b.Bytes())
+
+	return string(j[1 : len(j)-1])
+}
@@ -0,0 +1,56 @@
+package diff_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson/diff"
+)
+
+func TestHTMLFormatter_Format(t *testing.T) {
+	var left, right interface{}
+
+	require.NoError(t, json.Unmarshal([]byte(`{"a":1,"b":[1,2],"c":{"d":"<e>"}}`), &left))
+	require.NoError(t, json.Unmarshal([]byte(`{"a":2,"b":[1,3],"c":{"d":"<e>"}}`), &right))
+
+	h, err := diff.NewHTMLFormatter(left).Format(diff.New().CompareValues(left, right))
+	require.NoError(t, err)
+
+	assert.Equal(t, `<div class="jsondiff" style="font-family:monospace;white-space:pre;line-height:1.4">`+
+		`<details open><summary style="cursor:pointer">  {</summary><div style="padding-left:2ch">`+
+		`<div class="deleted" style="background:#ffeef0;color:#b31d28">- "a": 1</div>`+
+		`<div class="added" style="background:#e6ffed;color:#22863a">+ "a": 2</div>`+
+		`<details open><summary style="cursor:pointer">  "b": [</summary><div style="padding-left:2ch">`+
+		`<div class="same">  1</div>`+
+		`<div class="deleted" style="background:#ffeef0;color:#b31d28">- 2</div>`+
+		`<div class="added" style="background:#e6ffed;color:#22863a">+ 3</div>`+
+		`</div><div class="same">  ]</div></details>`+
+		`<details><summary style="cursor:pointer">  "c": {</summary><div style="padding-left:2ch">`+
+		`<div class="same">  "d": "&lt;e&gt;"</div>`+
+		`</div><div class="same">  }</div></details>`+
+		`</div><div class="same">  }</div></details></div>`, h)
+}
+
+func TestHTMLFormatter_Format_textDiff(t *testing.T) {
+	left := map[string]interface{}{"t": "a long text that is diffed by characters"}
+	right := map[string]interface{}{"t": "a long text that is diffed by words"}
+
+	h, err := diff.NewHTMLFormatter(left).Format(diff.New().CompareValues(left, right))
+	require.NoError(t, err)
+
+	assert.Contains(t, h, `- "t": "a long text that is diffed by `+
+		`<del style="background:#fdb8c0;text-decoration:none">character</del>s"`)
+	assert.Contains(t, h, `+ "t": "a long text that is diffed by `+
+		`<ins style="background:#acf2bd;text-decoration:none">word</ins>s"`)
+}
+
+func TestHTMLFormatter_Format_root(t *testing.T) {
+	h, err := diff.NewHTMLFormatter(1.0).Format(diff.New().CompareValues(1.0, "a"))
+	require.NoError(t, err)
+
+	assert.Equal(t, `<div class="jsondiff" style="font-family:monospace;white-space:pre;line-height:1.4">`+
+		`<div class="deleted" style="background:#ffeef0;color:#b31d28">- 1</div>`+
+		`<div class="added" style="background:#e6ffed;color:#22863a">+ "a"</div></div>`, h)
+}
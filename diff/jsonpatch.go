@@ -0,0 +1,307 @@
+package diff
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// JSON Patch operation names.
+const (
+	JSONPatchAdd     = "add"
+	JSONPatchRemove  = "remove"
+	JSONPatchReplace = "replace"
+	JSONPatchMove    = "move"
+	JSONPatchCopy    = "copy"
+	JSONPatchTest    = "test"
+)
+
+// JSONPatchOperation is an operation of JSON Patch (RFC 6902).
+type JSONPatchOperation struct {
+	Op    string      `json:"op"`
+	From  string      `json:"from,omitempty"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// MarshalJSON keeps null value for operations that require value.
+func (o JSONPatchOperation) MarshalJSON() ([]byte, error) {
+	switch o.Op {
+	case JSONPatchAdd, JSONPatchReplace, JSONPatchTest:
+		return marshalJSON(struct {
+			Op    string      `json:"op"`
+			Path  string      `json:"path"`
+			Value interface{} `json:"value"`
+		}{Op: o.Op, Path: o.Path, Value: o.Value})
+	default:
+		type op JSONPatchOperation
+
+		return marshalJSON(op(o))
+	}
+}
+
+// NewJSONPatchFormatter creates a new JSONPatchFormatter instance with the specified left data.
+func NewJSONPatchFormatter(left interface{}) *JSONPatchFormatter {
+	return &JSONPatchFormatter{
+		left: left,
+	}
+}
+
+// JSONPatchFormatter converts differences into JSON Patch (RFC 6902) document that transforms left data into right.
+type JSONPatchFormatter struct {
+	left interface{}
+	ops  []JSONPatchOperation
+}
+
+// Format returns JSON Patch document of the provided Diff.
+func (f *JSONPatchFormatter) Format(diff Diff) (result string, err error) {
+	ops, err := f.Operations(diff)
+	if err != nil {
+		return "", err
+	}
+
+	j, err := marshalJSON(ops)
+	if err != nil {
+		return "", err
+	}
+
+	return string(j), nil
+}
+
+// Operations returns JSON Patch operations of the provided Diff.
+func (f *JSONPatchFormatter) Operations(diff Diff) ([]JSONPatchOperation, error) {
+	f.ops = make([]JSONPatchOperation, 0)
+
+	if err := f.processValue(f.left, diff.Deltas(), nil); err != nil {
+		return nil, err
+	}
+
+	return f.ops, nil
+}
+
+func (f *JSONPatchFormatter) add(op string, path []string, value interface{}) {
+	f.ops = append(f.ops, JSONPatchOperation{Op: op, Path: Pointer(path...), Value: value})
+}
+
+func (f *JSONPatchFormatter) processValue(left interface{}, deltas []Delta, path []string) error {
+	if rootChanged(deltas) {
+		// Value is replaced with a value of another type.
+		for _, delta := range deltas {
+			if err := f.processDelta(left, delta, path); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	switch l := left.(type) {
+	case map[string]interface{}:
+		return f.processObject(l, deltas, path)
+	case []interface{}:
+		return f.processArray(l, deltas, path)
+	}
+
+	for _, delta := range deltas {
+		if err := f.processDelta(left, delta, path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f *JSONPatchFormatter) processObject(left map[string]interface{}, deltas []Delta, path []string) error {
+	for _, delta := range deltas {
+		switch d := delta.(type) {
+		case *Deleted:
+			f.add(JSONPatchRemove, childPath(path, d.PrePosition()), nil)
+		case *Added:
+			f.add(JSONPatchAdd, childPath(path, d.PostPosition()), d.Value)
+		case *Moved:
+			return errors.New("moved deltas are not supported for objects")
+		case PostDelta:
+			var value interface{}
+			if n, ok := d.PostPosition().(Name); ok {
+				value = left[string(n)]
+			}
+
+			if err := f.processDelta(value, delta, path); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unknown Delta type detected: %T", delta)
+		}
+	}
+
+	return nil
+}
+
+// processDelta adds operations of a delta that changes value in place.
+func (f *JSONPatchFormatter) processDelta(value interface{}, delta Delta, path []string) error {
+	d, ok := delta.(PostDelta)
+	if !ok {
+		return fmt.Errorf("unexpected Delta type detected: %T", delta)
+	}
+
+	return f.processChange(value, delta, childPath(path, d.PostPosition()))
+}
+
+// processChange adds operations of a delta that changes value at path.
+func (f *JSONPatchFormatter) processChange(value interface{}, delta Delta, path []string) error {
+	switch d := delta.(type) {
+	case *Object:
+		return f.processValue(value, d.Deltas, path)
+	case *Array:
+		return f.processValue(value, d.Deltas, path)
+	case *Modified:
+		f.add(JSONPatchReplace, path, d.NewValue)
+	case *TextDiff:
+		f.add(JSONPatchReplace, path, d.NewValue)
+	default:
+		return fmt.Errorf("unexpected Delta type detected: %T", delta)
+	}
+
+	return nil
+}
+
+// processArray adds operations that follow the order of deltas application:
+// deleted items are removed first, then the array is rearranged with added and moved items
+// and then remaining items are changed in place. Changes of items of unordered arrays are applied
+// to items at their current positions.
+func (f *JSONPatchFormatter) processArray(left []interface{}, deltas []Delta, path []string) error {
+	var (
+		deleted     []int             // Left indexes of deleted items.
+		moved       = map[int]bool{}  // Left indexes of moved items.
+		inserted    []PostDelta       // Added or moved items.
+		changed     = map[int]Delta{} // Changes of items by resulting index.
+		changedLeft = map[int]Delta{} // Changes of items of unordered arrays by left index.
+	)
+
+	for _, delta := range deltas {
+		switch d := delta.(type) {
+		case *Deleted:
+			deleted = append(deleted, int(d.PrePosition().(Index)))
+		case *Moved:
+			moved[int(d.PrePosition().(Index))] = true
+
+			inserted = append(inserted, d)
+
+			if delta, ok := d.Delta.(Delta); ok {
+				changed[int(d.PostPosition().(Index))] = delta
+			}
+		case *Added:
+			inserted = append(inserted, d)
+		case PostDelta:
+			if from, ok := changedFrom(delta); ok {
+				changedLeft[int(from)] = delta
+			} else {
+				changed[int(d.PostPosition().(Index))] = delta
+			}
+		default:
+			return fmt.Errorf("unknown Delta type detected: %T", delta)
+		}
+	}
+
+	// Items are identified by left index, added items have negative identifiers.
+	current := make([]int, len(left))
+	for i := range current {
+		current[i] = i
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(deleted)))
+
+	for _, i := range deleted {
+		f.add(JSONPatchRemove, append(path[:len(path):len(path)], Index(i).String()), nil)
+
+		current = append(current[:i:i], current[i+1:]...)
+	}
+
+	result := make([]int, 0, len(current)+len(inserted))
+
+	for _, id := range current {
+		if !moved[id] {
+			result = append(result, id)
+		}
+	}
+
+	sort.Slice(inserted, func(i, j int) bool {
+		return inserted[i].PostPosition().CompareTo(inserted[j].PostPosition())
+	})
+
+	added := make(map[int]interface{})
+
+	for k, d := range inserted {
+		id := -1 - k
+
+		if m, ok := d.(*Moved); ok {
+			id = int(m.PrePosition().(Index))
+		} else {
+			added[id] = d.(*Added).Value
+		}
+
+		t := int(d.PostPosition().(Index))
+		if t > len(result) {
+			t = len(result)
+		}
+
+		result = append(result[:t], append([]int{id}, result[t:]...)...)
+	}
+
+	for t, id := range result {
+		itemPath := append(path[:len(path):len(path)], Index(t).String())
+
+		if t < len(current) && current[t] == id {
+			continue
+		}
+
+		if value, ok := added[id]; ok {
+			f.add(JSONPatchAdd, itemPath, value)
+
+			current = append(current[:t], append([]int{id}, current[t:]...)...)
+
+			continue
+		}
+
+		for k := t + 1; k < len(current); k++ {
+			if current[k] == id {
+				f.ops = append(f.ops, JSONPatchOperation{
+					Op:   JSONPatchMove,
+					From: Pointer(append(path[:len(path):len(path)], Index(k).String())...),
+					Path: Pointer(itemPath...),
+				})
+
+				current = append(current[:k], current[k+1:]...)
+				current = append(current[:t], append([]int{id}, current[t:]...)...)
+
+				break
+			}
+		}
+	}
+
+	for t, id := range result {
+		if d, ok := changedLeft[id]; ok && id >= 0 {
+			if err := f.processChange(left[id], d, append(path[:len(path):len(path)], Index(t).String())); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		d, ok := changed[t]
+		if !ok {
+			continue
+		}
+
+		var value interface{}
+		if id >= 0 {
+			value = left[id]
+		}
+
+		if err := f.processDelta(value, d, path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
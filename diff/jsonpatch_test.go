@@ -0,0 +1,86 @@
+package diff_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson/diff"
+)
+
+func TestJSONPatchFormatter_Format(t *testing.T) {
+	for _, tc := range []struct {
+		left, right string
+		patch       string
+	}{
+		{
+			left:  `{"a":1,"b":[1,2,3],"c/d~":{"x":null}}`,
+			right: `{"a":2,"b":[3,1,2,4],"c/d~":{"x":1,"y":null},"n":null}`,
+			patch: `[{"op":"replace","path":"/a","value":2},{"op":"move","from":"/b/2","path":"/b/0"},` +
+				`{"op":"add","path":"/b/3","value":4},{"op":"replace","path":"/c~1d~0/x","value":1},` +
+				`{"op":"add","path":"/c~1d~0/y","value":null},{"op":"add","path":"/n","value":null}]`,
+		},
+		{
+			left:  `{"a":[1,2,3,4],"b":true}`,
+			right: `{"a":[1,4]}`,
+			patch: `[{"op":"remove","path":"/a/2"},{"op":"remove","path":"/a/1"},{"op":"remove","path":"/b"}]`,
+		},
+		{
+			left:  `[1,2,3,4,5]`,
+			right: `[5,4,3,2,1]`,
+			patch: `[{"op":"move","from":"/4","path":"/0"},{"op":"move","from":"/4","path":"/1"},` +
+				`{"op":"move","from":"/4","path":"/2"},{"op":"move","from":"/4","path":"/3"}]`,
+		},
+		{
+			left:  `[{"a":1},{"b":2},3]`,
+			right: `[3,{"a":1},{"b":3}]`,
+			patch: `[{"op":"move","from":"/2","path":"/0"},{"op":"replace","path":"/2/b","value":3}]`,
+		},
+		{
+			left:  `{"a":"a long text that is diffed by characters"}`,
+			right: `{"a":"a long text that is diffed by words"}`,
+			patch: `[{"op":"replace","path":"/a","value":"a long text that is diffed by words"}]`,
+		},
+		{
+			left:  `{"id":1}`,
+			right: `{"id":"<ignore-diff>"}`,
+			patch: `[{"op":"replace","path":"/id","value":"<ignore-diff>"}]`,
+		},
+		{
+			left:  `1`,
+			right: `"a"`,
+			patch: `[{"op":"replace","path":"","value":"a"}]`,
+		},
+		{
+			left:  `[]`,
+			right: `2`,
+			patch: `[{"op":"replace","path":"","value":2}]`,
+		},
+		{
+			left:  `{"a":1}`,
+			right: `[1]`,
+			patch: `[{"op":"replace","path":"","value":[1]}]`,
+		},
+		{
+			left:  `{"a":1}`,
+			right: `{"a":1}`,
+			patch: `[]`,
+		},
+	} {
+		t.Run(tc.left, func(t *testing.T) {
+			var left, right interface{}
+
+			require.NoError(t, json.Unmarshal([]byte(tc.left), &left))
+			require.NoError(t, json.Unmarshal([]byte(tc.right), &right))
+
+			patch, err := diff.NewJSONPatchFormatter(left).Format(diff.New().CompareValues(left, right))
+			require.NoError(t, err)
+			assert.Equal(t, tc.patch, patch)
+
+			applied, err := diff.ApplyJSONPatch([]byte(tc.left), []byte(patch))
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.right, string(applied))
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package diff
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// arrayKey is an identity property of items of arrays at matching locations.
+type arrayKey struct {
+	selector Selector
+	key      string
+}
+
+// arrayKey returns identity property of items of array at current location.
+//
+// Selectors can refer to arrays, e.g. "/users", or to their items, e.g. "/users/*".
+func (differ *Differ) arrayKey() (string, bool) {
+	for _, k := range differ.arrayKeys {
+		if k.selector.Match(differ.path) {
+			return k.key, true
+		}
+
+		if n := len(k.selector); n > 0 && k.selector[n-1] == "*" && k.selector[:n-1].Match(differ.path) {
+			return k.key, true
+		}
+	}
+
+	return "", false
+}
+
+// compareArraysByKey pairs items of arrays by identity property, see pairedArrayDeltas.
+//
+// False is returned if any of items is not an object with a unique identity.
+func (differ *Differ) compareArraysByKey(left, right []interface{}, key string) ([]Delta, bool) {
+	leftIDs, ok := itemIdentities(left, key)
+	if !ok {
+		return nil, false
+	}
+
+	rightIDs, ok := itemIdentities(right, key)
+	if !ok {
+		return nil, false
+	}
+
+	rightIndex := make(map[string]int, len(rightIDs))
+	for i, id := range rightIDs {
+		rightIndex[id] = i
+	}
+
+	pairs := make([]arrayPair, 0, len(left))
+
+	for li, id := range leftIDs {
+		if ri, found := rightIndex[id]; found {
+			same, delta := differ.compareValues(Index(ri), left[li], right[ri])
+			pairs = append(pairs, arrayPair{left: li, right: ri, same: same, delta: delta})
+		}
+	}
+
+	return pairedArrayDeltas(left, right, pairs), true
+}
+
+// arrayPair is a pair of left and right array items with a delta of their difference.
+type arrayPair struct {
+	left, right int
+	same        bool
+	delta       Delta
+}
+
+// pairedArrayDeltas returns deltas of arrays with paired items, pairs are ordered by left index.
+//
+// Pairs that keep relative order are changed in place, other pairs are moved, deltas of
+// their changes are positioned by right items. Unpaired items are deleted or added.
+func pairedArrayDeltas(left, right []interface{}, pairs []arrayPair) []Delta {
+	var (
+		deltas      = make([]Delta, 0)
+		pairedLeft  = make(map[int]bool, len(pairs))
+		pairedRight = make(map[int]bool, len(pairs))
+		rightPairs  = make([]int, 0, len(pairs))
+	)
+
+	for _, p := range pairs {
+		pairedLeft[p.left] = true
+		pairedRight[p.right] = true
+		rightPairs = append(rightPairs, p.right)
+	}
+
+	for li := range left {
+		if !pairedLeft[li] {
+			deltas = append(deltas, NewDeleted(Index(li), left[li]))
+		}
+	}
+
+	inOrder := longestIncreasing(rightPairs)
+
+	for k, p := range pairs {
+		switch {
+		case !inOrder[k]:
+			deltas = append(deltas, NewMoved(Index(p.left), Index(p.right), left[p.left], p.delta))
+		case !p.same:
+			deltas = append(deltas, p.delta)
+		}
+	}
+
+	for ri := range right {
+		if !pairedRight[ri] {
+			deltas = append(deltas, NewAdded(Index(ri), right[ri]))
+		}
+	}
+
+	return deltas
+}
+
+// itemIdentities returns encoded values of identity property of array items.
+func itemIdentities(items []interface{}, key string) ([]string, bool) {
+	ids := make([]string, 0, len(items))
+	seen := make(map[string]bool, len(items))
+
+	for _, item := range items {
+		o, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		v, ok := o[key]
+		if !ok {
+			return nil, false
+		}
+
+		id, err := json.Marshal(v)
+		if err != nil || seen[string(id)] {
+			return nil, false
+		}
+
+		seen[string(id)] = true
+		ids = append(ids, string(id))
+	}
+
+	return ids, true
+}
+
+// longestIncreasing marks members of the longest strictly increasing subsequence.
+func longestIncreasing(seq []int) []bool {
+	var (
+		tails = make([]int, 0, len(seq)) // Indexes of smallest tails of subsequences by length.
+		prev  = make([]int, len(seq))
+	)
+
+	for i, v := range seq {
+		n := sort.Search(len(tails), func(k int) bool { return seq[tails[k]] >= v })
+
+		prev[i] = -1
+		if n > 0 {
+			prev[i] = tails[n-1]
+		}
+
+		if n == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[n] = i
+		}
+	}
+
+	members := make([]bool, len(seq))
+
+	if len(tails) > 0 {
+		for i := tails[len(tails)-1]; i >= 0; i = prev[i] {
+			members[i] = true
+		}
+	}
+
+	return members
+}
+
+func sortedStringKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
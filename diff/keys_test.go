@@ -0,0 +1,179 @@
+package diff_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson/diff"
+)
+
+func TestDiffer_CompareValues_arrayKeys(t *testing.T) {
+	var left, right interface{}
+
+	leftJSON := `{"users":[{"id":1,"name":"a"},{"id":2,"name":"b"},{"id":3,"name":"c"},{"id":4,"name":"d"}]}`
+	rightJSON := `{"users":[{"id":3,"name":"c"},{"id":1,"name":"a"},{"id":5,"name":"e"},{"id":4,"name":"D"}]}`
+
+	require.NoError(t, json.Unmarshal([]byte(leftJSON), &left))
+	require.NoError(t, json.Unmarshal([]byte(rightJSON), &right))
+
+	d := diff.NewWithConfig(diff.DifferConfig{ArrayKeys: map[string]string{"/users": "id"}}).CompareValues(left, right)
+
+	s, err := diff.NewPathSummaryFormatter(diff.PathSummaryFormatterConfig{}).Format(d)
+	require.NoError(t, err)
+	assert.Equal(t, `/users/1: removed {"id":2,"name":"b"}
+/users/1: moved from /users/0
+/users/3/name: "d" -> "D"
+/users/2: added {"id":5,"name":"e"}
+`, s)
+
+	a, err := diff.NewASCIIFormatter(left, diff.ASCIIFormatterConfig{}).Format(d)
+	require.NoError(t, err)
+	assert.Equal(t, ` {
+   "users": [
+-    {
+-      "id": 1,
+-      "name": "a"
+-    },
+-    {
+-      "id": 2,
+-      "name": "b"
+-    },
+     {
+       "id": 3,
+       "name": "c"
+     },
++    {
++      "id": 1,
++      "name": "a"
++    },
++    {
++      "id": 5,
++      "name": "e"
++    },
+     {
+       "id": 4,
+-      "name": "d"
++      "name": "D"
+     }
+   ]
+ }
+`, a)
+
+	patch, err := diff.NewJSONPatchFormatter(left).Format(d)
+	require.NoError(t, err)
+
+	applied, err := diff.ApplyJSONPatch([]byte(leftJSON), []byte(patch))
+	require.NoError(t, err)
+	assert.JSONEq(t, rightJSON, string(applied))
+}
+
+func TestDiffer_CompareValues_arrayKeysMovedChanged(t *testing.T) {
+	var left, right interface{}
+
+	leftJSON := `[{"id":"a","v":1},{"id":"b","v":2}]`
+	rightJSON := `[{"id":"b","v":3},{"id":"a","v":1}]`
+
+	require.NoError(t, json.Unmarshal([]byte(leftJSON), &left))
+	require.NoError(t, json.Unmarshal([]byte(rightJSON), &right))
+
+	d := diff.NewWithConfig(diff.DifferConfig{ArrayKeys: map[string]string{"": "id"}}).CompareValues(left, right)
+
+	s, err := diff.NewPathSummaryFormatter(diff.PathSummaryFormatterConfig{}).Format(d)
+	require.NoError(t, err)
+	assert.Equal(t, "/1: moved from /0\n/0/v: 2 -> 3\n", s)
+
+	a, err := diff.NewASCIIFormatter(left, diff.ASCIIFormatterConfig{}).Format(d)
+	require.NoError(t, err)
+	assert.Equal(t, ` [
+-  {
+-    "id": "a",
+-    "v": 1
+-  },
+   {
+     "id": "b",
+-    "v": 2
++    "v": 3
+   },
++  {
++    "id": "a",
++    "v": 1
++  }
+ ]
+`, a)
+
+	patch, err := diff.NewJSONPatchFormatter(left).Format(d)
+	require.NoError(t, err)
+
+	applied, err := diff.ApplyJSONPatch([]byte(leftJSON), []byte(patch))
+	require.NoError(t, err)
+	assert.JSONEq(t, rightJSON, string(applied))
+
+	// Items without identity are compared by similarity.
+	d = diff.NewWithConfig(diff.DifferConfig{ArrayKeys: map[string]string{"": "name"}}).CompareValues(left, right)
+
+	s, err = diff.NewPathSummaryFormatter(diff.PathSummaryFormatterConfig{}).Format(d)
+	require.NoError(t, err)
+	assert.NotContains(t, s, "moved from")
+}
+
+func TestDiffer_CompareValues_arrayKeysMovedModified(t *testing.T) {
+	var left, right interface{}
+
+	leftJSON := `[{"id":"a","v":1},{"id":"b"},{"id":"c"}]`
+	rightJSON := `[{"id":"b"},{"id":"c"},{"id":"a","v":2}]`
+
+	require.NoError(t, json.Unmarshal([]byte(leftJSON), &left))
+	require.NoError(t, json.Unmarshal([]byte(rightJSON), &right))
+
+	d := diff.NewWithConfig(diff.DifferConfig{ArrayKeys: map[string]string{"": "id"}}).CompareValues(left, right)
+
+	s, err := diff.NewPathSummaryFormatter(diff.PathSummaryFormatterConfig{}).Format(d)
+	require.NoError(t, err)
+	assert.Equal(t, "/2: moved from /0\n/2/v: 1 -> 2\n", s)
+
+	a, err := diff.NewASCIIFormatter(left, diff.ASCIIFormatterConfig{}).Format(d)
+	require.NoError(t, err)
+	assert.Equal(t, ` [
+-  {
+-    "id": "a",
+-    "v": 1
+-  },
+   {
+     "id": "b"
+   },
+   {
+     "id": "c"
+   },
+   {
+     "id": "a",
+-    "v": 1
++    "v": 2
+   }
+ ]
+`, a)
+
+	patch, err := diff.NewJSONPatchFormatter(left).Format(d)
+	require.NoError(t, err)
+
+	applied, err := diff.ApplyJSONPatch([]byte(leftJSON), []byte(patch))
+	require.NoError(t, err)
+	assert.JSONEq(t, rightJSON, string(applied))
+}
+
+func TestDiffer_CompareValues_arrayKeysItemSelector(t *testing.T) {
+	var left, right interface{}
+
+	require.NoError(t, json.Unmarshal([]byte(`{"users":[{"id":1,"v":1},{"id":2}]}`), &left))
+	require.NoError(t, json.Unmarshal([]byte(`{"users":[{"id":2},{"id":1,"v":2}]}`), &right))
+
+	// Selectors of arrays and of their items are equivalent.
+	for _, sel := range []string{"/users", "/users/*", "$.users[*]"} {
+		d := diff.NewWithConfig(diff.DifferConfig{ArrayKeys: map[string]string{sel: "id"}}).CompareValues(left, right)
+
+		s, err := diff.NewPathSummaryFormatter(diff.PathSummaryFormatterConfig{}).Format(d)
+		require.NoError(t, err)
+		assert.Equal(t, "/users/1: moved from /users/0\n/users/1/v: 1 -> 2\n", s, sel)
+	}
+}
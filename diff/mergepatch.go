@@ -0,0 +1,107 @@
+package diff
+
+import "fmt"
+
+// MergePatch returns minimal JSON Merge Patch (RFC 7386) that transforms left JSON document into right.
+//
+// Merge Patch can not set null values of object members, such differences are reported with ErrInvalidPatch.
+func MergePatch(left, right []byte) ([]byte, error) {
+	l, err := decodeValue(left)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode left document: %w", err)
+	}
+
+	r, err := decodeValue(right)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode right document: %w", err)
+	}
+
+	patch, err := MergePatchValue(l, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalJSON(patch)
+}
+
+// MergePatchValue returns minimal JSON Merge Patch (RFC 7386) that transforms left decoded value into right.
+func MergePatchValue(left, right interface{}) (interface{}, error) {
+	return mergePatch(left, right, nil)
+}
+
+func mergePatch(left, right interface{}, path []string) (interface{}, error) {
+	r, ok := right.(map[string]interface{})
+	if !ok {
+		return right, nil
+	}
+
+	l, ok := left.(map[string]interface{})
+	if !ok {
+		return r, checkMergeValue(r, path)
+	}
+
+	differ := NewWithConfig(DifferConfig{SemanticNumbers: true})
+	patch := make(map[string]interface{})
+
+	for k := range l {
+		if _, found := r[k]; !found {
+			patch[k] = nil
+		}
+	}
+
+	for _, k := range sortedKeys(r) {
+		rv := r[k]
+		p := append(path[:len(path):len(path)], k)
+
+		lv, found := l[k]
+		if found && !differ.CompareValues(lv, rv).Modified() {
+			continue
+		}
+
+		if rv == nil {
+			return nil, fmt.Errorf("%w: null value at %s can not be set with merge patch", ErrInvalidPatch, Pointer(p...))
+		}
+
+		if !found {
+			if err := checkMergeValue(rv, p); err != nil {
+				return nil, err
+			}
+
+			patch[k] = rv
+
+			continue
+		}
+
+		v, err := mergePatch(lv, rv, p)
+		if err != nil {
+			return nil, err
+		}
+
+		patch[k] = v
+	}
+
+	return patch, nil
+}
+
+// checkMergeValue checks that object value has no null members that would be lost by merge patch.
+func checkMergeValue(v interface{}, path []string) error {
+	o, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	for _, k := range sortedKeys(o) {
+		v := o[k]
+		p := append(path[:len(path):len(path)], k)
+
+		if v == nil {
+			return fmt.Errorf("%w: null value at %s can not be set with merge patch", ErrInvalidPatch, Pointer(p...))
+		}
+
+		if err := checkMergeValue(v, p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
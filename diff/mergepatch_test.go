@@ -0,0 +1,47 @@
+package diff_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson/diff"
+)
+
+func TestMergePatch(t *testing.T) {
+	for _, tc := range []struct {
+		left, right string
+		patch       string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"a":"b","b":"c"}`, `{"b":"c"}`},
+		{`{"a":"b","b":"c"}`, `{"b":"c"}`, `{"a":null}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":{"b":"c","d":1}}`, `{"a":{"b":"d","d":1.0}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[{"b":"c"},null]}`, `{"a":[{"b":"c"},null]}`},
+		{`{"a":1,"b":{"c":[1,2]}}`, `{"a":1,"b":{"c":[1,2]}}`, `{}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`[1]`, `{"a":{"b":1}}`, `{"a":{"b":1}}`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"id":1,"b":"&"}`, `{"id":"<ignore-diff>","b":"&"}`, `{"id":"<ignore-diff>"}`},
+	} {
+		t.Run(tc.left+tc.right, func(t *testing.T) {
+			patch, err := diff.MergePatch([]byte(tc.left), []byte(tc.right))
+			require.NoError(t, err)
+			assert.Equal(t, tc.patch, string(patch))
+
+			applied, err := diff.ApplyMergePatch([]byte(tc.left), patch)
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.right, string(applied))
+		})
+	}
+}
+
+func TestMergePatch_null(t *testing.T) {
+	_, err := diff.MergePatch([]byte(`{"a":1}`), []byte(`{"a":null}`))
+	assert.EqualError(t, err, "invalid patch: null value at /a can not be set with merge patch")
+
+	_, err = diff.MergePatch([]byte(`{}`), []byte(`{"a":{"b":{"c":null}}}`))
+	assert.EqualError(t, err, "invalid patch: null value at /a/b/c can not be set with merge patch")
+}
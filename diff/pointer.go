@@ -0,0 +1,190 @@
+package diff
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidSelector is returned for malformed selector.
+var ErrInvalidSelector = errors.New("invalid selector")
+
+// Pointer returns JSON Pointer (RFC 6901) that refers to a value by reference tokens.
+func Pointer(tokens ...string) string {
+	if len(tokens) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+
+	for _, t := range tokens {
+		b.WriteByte('/')
+		b.WriteString(pointerEscaper.Replace(t))
+	}
+
+	return b.String()
+}
+
+// ParsePointer splits JSON Pointer (RFC 6901) into unescaped reference tokens.
+func ParsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+
+	if pointer[0] != '/' {
+		return nil, errors.New("JSON Pointer must start with /: " + pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = pointerUnescaper.Replace(t)
+	}
+
+	return tokens, nil
+}
+
+var (
+	pointerEscaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
+
+// Selector is a JSON Pointer pattern that matches locations in a document.
+//
+// Token "*" matches any single reference token, token "**" matches any number of reference tokens,
+// for example "/items/*/id" or "**/updatedAt".
+type Selector []string
+
+// ParseSelector parses a JSON Pointer pattern or a JSONPath-like expression into Selector.
+//
+// Supported forms are "/items/*/id", "**/updatedAt" (relative to document root),
+// "$.items[*].id" and "$..updatedAt".
+func ParseSelector(s string) Selector {
+	if strings.HasPrefix(s, "$") {
+		return parseJSONPath(s[1:])
+	}
+
+	s = strings.TrimPrefix(s, "/")
+	if s == "" {
+		return Selector{}
+	}
+
+	tokens := strings.Split(s, "/")
+	for i, t := range tokens {
+		tokens[i] = pointerUnescaper.Replace(t)
+	}
+
+	return tokens
+}
+
+// ParseSelectorStrict parses selector like ParseSelector, but reports malformed expressions,
+// e.g. "$.items[*" or "/a~2b", instead of guessing their meaning.
+func ParseSelectorStrict(s string) (Selector, error) {
+	var err error
+
+	if strings.HasPrefix(s, "$") {
+		err = checkJSONPath(s[1:])
+	} else if strings.Contains(strings.NewReplacer("~0", "", "~1", "").Replace(s), "~") {
+		err = errors.New("invalid escape sequence")
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %v", ErrInvalidSelector, s, err)
+	}
+
+	return ParseSelector(s), nil
+}
+
+// checkJSONPath checks syntax of JSONPath-like expression without leading "$".
+func checkJSONPath(s string) error {
+	for s != "" {
+		switch {
+		case strings.HasPrefix(s, ".."):
+			s = s[2:]
+
+			if s == "" || s[0] == '.' {
+				return errors.New("missing name after ..")
+			}
+		case s[0] == '.':
+			s = s[1:]
+
+			if s == "" || s[0] == '.' || s[0] == '[' {
+				return errors.New("missing name after .")
+			}
+		case s[0] == '[':
+			end := strings.IndexByte(s, ']')
+			if end == -1 {
+				return errors.New("missing ]")
+			}
+
+			if strings.Trim(s[1:end], `'"`) == "" {
+				return errors.New("empty []")
+			}
+
+			s = s[end+1:]
+		default:
+			end := strings.IndexAny(s, ".[")
+			if end == -1 {
+				end = len(s)
+			}
+
+			s = s[end:]
+		}
+	}
+
+	return nil
+}
+
+func parseJSONPath(s string) Selector {
+	sel := Selector{}
+
+	for s != "" {
+		switch {
+		case strings.HasPrefix(s, ".."):
+			sel = append(sel, "**")
+			s = s[2:]
+		case s[0] == '.':
+			s = s[1:]
+		case s[0] == '[':
+			end := strings.IndexByte(s, ']')
+			if end == -1 {
+				end = len(s) - 1
+			}
+
+			sel = append(sel, strings.Trim(s[1:end], `'"`))
+			s = s[end+1:]
+		default:
+			end := strings.IndexAny(s, ".[")
+			if end == -1 {
+				end = len(s)
+			}
+
+			sel = append(sel, s[:end])
+			s = s[end:]
+		}
+	}
+
+	return sel
+}
+
+// Match reports whether reference tokens of a location match selector.
+func (s Selector) Match(path []string) bool {
+	if len(s) == 0 {
+		return len(path) == 0
+	}
+
+	if s[0] == "**" {
+		for i := 0; i <= len(path); i++ {
+			if s[1:].Match(path[i:]) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if len(path) == 0 || (s[0] != "*" && s[0] != path[0]) {
+		return false
+	}
+
+	return s[1:].Match(path[1:])
+}
@@ -0,0 +1,39 @@
+package diff_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/swaggest/assertjson/diff"
+)
+
+func TestParseSelectorStrict(t *testing.T) {
+	for s, expected := range map[string]diff.Selector{
+		"/items/*/id":   {"items", "*", "id"},
+		"**/updatedAt":  {"**", "updatedAt"},
+		"/a~1b/c~0d":    {"a/b", "c~d"},
+		"$.items[*].id": {"items", "*", "id"},
+		"$..updatedAt":  {"**", "updatedAt"},
+		"$['a.b'][0]":   {"a.b", "0"},
+		"$":             {},
+		"":              {},
+	} {
+		sel, err := diff.ParseSelectorStrict(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, expected, sel, s)
+	}
+
+	for s, msg := range map[string]string{
+		"$.items[*": `invalid selector "$.items[*": missing ]`,
+		"$.items[]": `invalid selector "$.items[]": empty []`,
+		"$.items.":  `invalid selector "$.items.": missing name after .`,
+		"$.a..":     `invalid selector "$.a..": missing name after ..`,
+		"/a~2b":     `invalid selector "/a~2b": invalid escape sequence`,
+		"/items/a~": `invalid selector "/items/a~": invalid escape sequence`,
+	} {
+		_, err := diff.ParseSelectorStrict(s)
+		assert.EqualError(t, err, msg)
+		assert.True(t, errors.Is(err, diff.ErrInvalidSelector))
+	}
+}
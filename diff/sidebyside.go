@@ -0,0 +1,166 @@
+package diff
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// NewSideBySideFormatter creates a new SideBySideFormatter instance with the specified left data and configuration.
+func NewSideBySideFormatter(left interface{}, config SideBySideFormatterConfig) *SideBySideFormatter {
+	return &SideBySideFormatter{
+		left:   left,
+		config: config,
+	}
+}
+
+// SideBySideFormatter renders differences in two columns, left data on the left side and right data on the right side.
+type SideBySideFormatter struct {
+	left   interface{}
+	config SideBySideFormatterConfig
+}
+
+// SideBySideFormatterConfig specifies configuration options for side-by-side formatting.
+type SideBySideFormatterConfig struct {
+	ASCIIFormatterConfig
+
+	// ColumnWidth is a maximum width of a column, longer lines are wrapped, default 60.
+	ColumnWidth int
+}
+
+// Side-by-side gutters between columns.
+const (
+	SideBySideSame    = "   "
+	SideBySideChanged = " | "
+	SideBySideDeleted = " < "
+	SideBySideAdded   = " > "
+)
+
+// Format formats the differences in two columns.
+func (f *SideBySideFormatter) Format(diff Diff) (result string, err error) {
+	asciiConfig := f.config.ASCIIFormatterConfig
+	asciiConfig.Coloring = false
+
+	text, err := NewASCIIFormatter(f.left, asciiConfig).Format(diff)
+	if err != nil {
+		return "", err
+	}
+
+	return SideBySide(text, f.config), nil
+}
+
+// SideBySide converts output of ASCIIFormatter without coloring into two columns,
+// columns are colored if config.Coloring is enabled.
+//
+// Consecutive deleted and added lines are aligned with each other, lines that do not start
+// with a marker (e.g. "..." of a reduced diff) are shown in both columns. Lines longer than
+// column width are wrapped, so that no change is hidden.
+func SideBySide(asciiDiff string, config SideBySideFormatterConfig) string {
+	if config.ColumnWidth <= 0 {
+		config.ColumnWidth = 60
+	}
+
+	var (
+		res            strings.Builder
+		deleted, added []string
+	)
+
+	row := func(left, gutter, right string, leftMarker, rightMarker string) {
+		l, r := config.wrap(left), config.wrap(right)
+
+		for i := 0; i < len(l) || i < len(r); i++ {
+			var lc, rc string
+
+			if i < len(l) {
+				lc = config.column(l[i], leftMarker)
+			} else {
+				lc = config.pad("")
+			}
+
+			if i < len(r) {
+				rc = config.column(r[i], rightMarker)
+			}
+
+			res.WriteString(strings.TrimRight(lc+gutter+rc, " ") + "\n")
+		}
+	}
+
+	flush := func() {
+		for i := 0; i < len(deleted) || i < len(added); i++ {
+			switch {
+			case i >= len(added):
+				row(deleted[i], SideBySideDeleted, "", ASCIIDeleted, "")
+			case i >= len(deleted):
+				row("", SideBySideAdded, added[i], "", ASCIIAdded)
+			default:
+				row(deleted[i], SideBySideChanged, added[i], ASCIIDeleted, ASCIIAdded)
+			}
+		}
+
+		deleted, added = deleted[:0], added[:0]
+	}
+
+	for _, line := range strings.Split(asciiDiff, "\n") {
+		if line == "" {
+			continue
+		}
+
+		switch line[:1] {
+		case ASCIIDeleted:
+			if len(added) > 0 {
+				flush()
+			}
+
+			deleted = append(deleted, line[1:])
+		case ASCIIAdded:
+			added = append(added, line[1:])
+		case ASCIISame:
+			flush()
+			row(line[1:], SideBySideSame, line[1:], ASCIISame, ASCIISame)
+		default:
+			flush()
+			row(line, SideBySideSame, line, ASCIISame, ASCIISame)
+		}
+	}
+
+	flush()
+
+	return res.String()
+}
+
+// wrap splits a line into parts that fit the column width.
+func (c SideBySideFormatterConfig) wrap(line string) []string {
+	var parts []string
+
+	runes := []rune(line)
+
+	for len(runes) > c.ColumnWidth {
+		parts = append(parts, string(runes[:c.ColumnWidth]))
+		runes = runes[c.ColumnWidth:]
+	}
+
+	if len(runes) > 0 || len(parts) == 0 {
+		parts = append(parts, string(runes))
+	}
+
+	return parts
+}
+
+// column pads a part of a line to the column width.
+func (c SideBySideFormatterConfig) column(line, marker string) string {
+	line = c.pad(line)
+
+	if style, ok := ACSIIStyles[marker]; ok && c.Coloring {
+		return "\x1b[" + style + "m" + line + "\x1b[0m"
+	}
+
+	return line
+}
+
+// pad adds trailing spaces to fill the column width.
+func (c SideBySideFormatterConfig) pad(line string) string {
+	if n := c.ColumnWidth - utf8.RuneCountInString(line); n > 0 {
+		return line + strings.Repeat(" ", n)
+	}
+
+	return line
+}
@@ -0,0 +1,41 @@
+package diff_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson/diff"
+)
+
+func TestSideBySideFormatter_Format(t *testing.T) {
+	var left, right interface{}
+
+	require.NoError(t, json.Unmarshal([]byte(`{"a":1,"b":"prefix of a long value ends with foo","c":[1,2]}`), &left))
+	require.NoError(t, json.Unmarshal([]byte(`{"a":1,"b":"prefix of a long value ends with bar","c":[1]}`), &right))
+
+	d := diff.New().CompareValues(left, right)
+
+	s, err := diff.NewSideBySideFormatter(left, diff.SideBySideFormatterConfig{ColumnWidth: 20}).Format(d)
+	require.NoError(t, err)
+	assert.Equal(t, `{                      {
+  "a": 1,                "a": 1,
+  "b": "prefix of a  |   "b": "prefix of a
+long value ends with | long value ends with
+ [-foo-]",           |  {+bar+}",
+  "c": [                 "c": [
+    1,                     1,
+    2                <
+  ]                      ]
+}                      }
+`, s)
+
+	s, err = diff.NewSideBySideFormatter(left, diff.SideBySideFormatterConfig{
+		ASCIIFormatterConfig: diff.ASCIIFormatterConfig{Coloring: true},
+		ColumnWidth:          20,
+	}).Format(d)
+	require.NoError(t, err)
+	assert.Contains(t, s, "\x1b[30;41mlong value ends with\x1b[0m | \x1b[30;42mlong value ends with\x1b[0m\n")
+	assert.Contains(t, s, "\x1b[30;41m    2               \x1b[0m <\n")
+}
@@ -0,0 +1,308 @@
+package diff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrTrailingData is returned if there is data after JSON value of a stream.
+var ErrTrailingData = errors.New("trailing data")
+
+// CompareStreams compares JSON documents token by token without decoding them entirely.
+//
+// Only values that differ are decoded, so memory usage is bounded by the largest of decoded values
+// rather than size of documents:
+//   - objects with the same order of keys are walked member by member, if order of keys differs,
+//     the rest of both objects is decoded and compared as maps,
+//   - arrays are walked item by item and compared by position, so an inserted or removed item makes
+//     following items different and all of them are reported,
+//   - unordered arrays and arrays with ArrayKeys are decoded and compared entirely,
+//   - values of different types and different scalars are decoded.
+//
+// Each stream must have a single JSON value, ErrTrailingData is returned for data after it.
+//
+// Resulting deltas follow semantics of CompareValues, but there are no moved items of ordered arrays.
+func (differ *Differ) CompareStreams(left, right io.Reader) (Diff, error) {
+	l := json.NewDecoder(left)
+	l.UseNumber()
+
+	r := json.NewDecoder(right)
+	r.UseNumber()
+
+	same, delta, err := differ.compareStream(Root{}, l, r)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := streamEnd(l); err != nil {
+		return nil, fmt.Errorf("failed to read left: %w", err)
+	}
+
+	if err := streamEnd(r); err != nil {
+		return nil, fmt.Errorf("failed to read right: %w", err)
+	}
+
+	if same {
+		return &diff{deltas: []Delta{}}, nil
+	}
+
+	switch d := delta.(type) {
+	case *Object:
+		return &diff{deltas: d.Deltas}, nil
+	case *Array:
+		return &diff{deltas: d.Deltas}, nil
+	default:
+		return &diff{deltas: []Delta{delta}}, nil
+	}
+}
+
+func (differ *Differ) compareStream(position Position, left, right *json.Decoder) (same bool, delta Delta, err error) {
+	lt, err := left.Token()
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to read left: %w", err)
+	}
+
+	rt, err := right.Token()
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to read right: %w", err)
+	}
+
+	if lt == json.Delim('{') && rt == json.Delim('{') {
+		differ.push(position)
+		childDeltas, err := differ.streamObjects(left, right)
+		differ.pop(position)
+
+		if err != nil || len(childDeltas) == 0 {
+			return err == nil, nil, err
+		}
+
+		return false, NewObject(position, childDeltas), nil
+	}
+
+	if lt == json.Delim('[') && rt == json.Delim('[') {
+		differ.push(position)
+
+		if _, keyed := differ.arrayKey(); !keyed && !differ.unordered() {
+			childDeltas, err := differ.streamArrays(left, right)
+			differ.pop(position)
+
+			if err != nil || len(childDeltas) == 0 {
+				return err == nil, nil, err
+			}
+
+			return false, NewArray(position, childDeltas), nil
+		}
+
+		differ.pop(position)
+	}
+
+	lv, err := decodeToken(left, lt)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to read left: %w", err)
+	}
+
+	rv, err := decodeToken(right, rt)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to read right: %w", err)
+	}
+
+	same, delta = differ.compareValues(position, lv, rv)
+
+	return same, delta, nil
+}
+
+// streamObjects compares members of objects after opening delimiters.
+func (differ *Differ) streamObjects(left, right *json.Decoder) ([]Delta, error) {
+	var deltas []Delta
+
+	for left.More() && right.More() {
+		lk, err := readKey(left)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read left: %w", err)
+		}
+
+		rk, err := readKey(right)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read right: %w", err)
+		}
+
+		if lk != rk {
+			// Order of keys differs, remaining members are compared as maps.
+			lm, rm := make(map[string]interface{}), make(map[string]interface{})
+
+			if err := decodeMember(left, lk, lm); err != nil {
+				return nil, fmt.Errorf("failed to read left: %w", err)
+			}
+
+			if err := decodeMember(right, rk, rm); err != nil {
+				return nil, fmt.Errorf("failed to read right: %w", err)
+			}
+
+			return differ.compareRestObjects(deltas, left, right, lm, rm)
+		}
+
+		same, delta, err := differ.compareStream(Name(lk), left, right)
+		if err != nil {
+			return nil, err
+		}
+
+		if !same {
+			deltas = append(deltas, delta)
+		}
+	}
+
+	// Remaining members of one of objects.
+	return differ.compareRestObjects(deltas, left, right, make(map[string]interface{}), make(map[string]interface{}))
+}
+
+// compareRestObjects decodes remaining members of objects into maps and compares them.
+func (differ *Differ) compareRestObjects(
+	deltas []Delta,
+	left, right *json.Decoder,
+	lm, rm map[string]interface{},
+) ([]Delta, error) {
+	if err := decodeRestObject(left, lm); err != nil {
+		return nil, fmt.Errorf("failed to read left: %w", err)
+	}
+
+	if err := decodeRestObject(right, rm); err != nil {
+		return nil, fmt.Errorf("failed to read right: %w", err)
+	}
+
+	return append(deltas, differ.compareMaps(lm, rm)...), nil
+}
+
+// streamArrays compares items of arrays after opening delimiters by position.
+func (differ *Differ) streamArrays(left, right *json.Decoder) ([]Delta, error) {
+	var (
+		deltas []Delta
+		i      int
+	)
+
+	for ; left.More() && right.More(); i++ {
+		same, delta, err := differ.compareStream(Index(i), left, right)
+		if err != nil {
+			return nil, err
+		}
+
+		if !same {
+			deltas = append(deltas, delta)
+		}
+	}
+
+	for j := i; left.More(); j++ {
+		var v interface{}
+		if err := left.Decode(&v); err != nil {
+			return nil, fmt.Errorf("failed to read left: %w", err)
+		}
+
+		deltas = append(deltas, NewDeleted(Index(j), v))
+	}
+
+	for j := i; right.More(); j++ {
+		var v interface{}
+		if err := right.Decode(&v); err != nil {
+			return nil, fmt.Errorf("failed to read right: %w", err)
+		}
+
+		deltas = append(deltas, NewAdded(Index(j), v))
+	}
+
+	if _, err := left.Token(); err != nil {
+		return nil, fmt.Errorf("failed to read left: %w", err)
+	}
+
+	if _, err := right.Token(); err != nil {
+		return nil, fmt.Errorf("failed to read right: %w", err)
+	}
+
+	return deltas, nil
+}
+
+// streamEnd checks that there is no data after JSON value.
+func streamEnd(dec *json.Decoder) error {
+	offset := dec.InputOffset()
+
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%w after offset %d", ErrTrailingData, offset)
+	}
+
+	return nil
+}
+
+// decodeToken decodes value that starts with already read token.
+func decodeToken(dec *json.Decoder, tok json.Token) (interface{}, error) {
+	switch tok {
+	case json.Delim('{'):
+		m := make(map[string]interface{})
+
+		return m, decodeRestObject(dec, m)
+	case json.Delim('['):
+		a := make([]interface{}, 0)
+
+		for dec.More() {
+			var v interface{}
+			if err := dec.Decode(&v); err != nil {
+				return nil, err
+			}
+
+			a = append(a, v)
+		}
+
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+
+		return a, nil
+	case json.Delim('}'), json.Delim(']'):
+		return nil, fmt.Errorf("unexpected delimiter %v", tok)
+	}
+
+	return tok, nil
+}
+
+// decodeRestObject decodes remaining members of an object and its closing delimiter.
+func decodeRestObject(dec *json.Decoder, m map[string]interface{}) error {
+	for dec.More() {
+		k, err := readKey(dec)
+		if err != nil {
+			return err
+		}
+
+		if err := decodeMember(dec, k, m); err != nil {
+			return err
+		}
+	}
+
+	_, err := dec.Token()
+
+	return err
+}
+
+// decodeMember decodes value of an already read key.
+func decodeMember(dec *json.Decoder, key string, m map[string]interface{}) error {
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return err
+	}
+
+	m[key] = v
+
+	return nil
+}
+
+func readKey(dec *json.Decoder) (string, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return "", err
+	}
+
+	k, ok := tok.(string)
+	if !ok {
+		return "", errors.New("object key expected")
+	}
+
+	return k, nil
+}
@@ -0,0 +1,136 @@
+package diff_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson/diff"
+)
+
+func TestDiffer_CompareStreams(t *testing.T) {
+	for _, tc := range []struct {
+		left, right string
+		summary     string
+	}{
+		{`{"a":1,"b":[1,2,3]}`, `{"a":1,"b":[1,2,3]}`, ``},
+		{`{"a":1,"b":[1,2,3]}`, `{"b":[1,2,3],"a":1}`, ``},
+		{`{"a":1,"b":{"c":2,"d":3}}`, `{"a":1,"b":{"d":4,"c":2}}`, "/b/d: 3 -> 4\n"},
+		{`{"a":1,"b":2}`, `{"a":1,"b":2,"c":3}`, "/c: added 3\n"},
+		{`{"a":1,"b":2,"c":3}`, `{"a":1,"c":3}`, "/b: removed 2\n"},
+		{`[1,{"a":2},3]`, `[1,{"a":3},3,4,5]`, "/1/a: 2 -> 3\n/3: added 4\n/4: added 5\n"},
+		{`[1,2,3]`, `[1]`, "/1: removed 2\n/2: removed 3\n"},
+		{`{"a":[1,2]}`, `{"a":{"b":1}}`, "/a: [1,2] -> {\"b\":1}\n"},
+		{`"foo"`, `123`, "(root): \"foo\" -> 123\n"},
+		{`{"":{"x":1}}`, `{"":{"x":2}}`, "//x: 1 -> 2\n"},
+	} {
+		t.Run(tc.left+tc.right, func(t *testing.T) {
+			d, err := diff.New().CompareStreams(strings.NewReader(tc.left), strings.NewReader(tc.right))
+			require.NoError(t, err)
+
+			s, err := diff.NewPathSummaryFormatter(diff.PathSummaryFormatterConfig{}).Format(d)
+			require.NoError(t, err)
+			assert.Equal(t, tc.summary, s)
+			assert.Equal(t, tc.summary != "", d.Modified())
+		})
+	}
+}
+
+func TestDiffer_CompareStreams_unordered(t *testing.T) {
+	d, err := diff.NewWithConfig(diff.DifferConfig{UnorderedArrayPaths: []string{"/tags"}}).
+		CompareStreams(strings.NewReader(`{"tags":["a","b"],"list":[1,2]}`), strings.NewReader(`{"tags":["b","a"],"list":[2,1]}`))
+	require.NoError(t, err)
+
+	s, err := diff.NewPathSummaryFormatter(diff.PathSummaryFormatterConfig{}).Format(d)
+	require.NoError(t, err)
+	assert.Equal(t, "/list/0: 1 -> 2\n/list/1: 2 -> 1\n", s)
+}
+
+func TestDiffer_CompareStreams_invalid(t *testing.T) {
+	_, err := diff.New().CompareStreams(strings.NewReader(`{"a":[1,2}`), strings.NewReader(`{"a":[1,2]}`))
+	assert.EqualError(t, err, "failed to read left: invalid character '}' after array element")
+}
+
+func TestDiffer_CompareStreams_trailingData(t *testing.T) {
+	_, err := diff.New().CompareStreams(strings.NewReader(`{"a":1}`), strings.NewReader(`{"a":1} {"a":2}`))
+	assert.True(t, errors.Is(err, diff.ErrTrailingData))
+	assert.EqualError(t, err, "failed to read right: trailing data after offset 7")
+
+	_, err = diff.New().CompareStreams(strings.NewReader(`[1]]`), strings.NewReader(`[1]`))
+	assert.EqualError(t, err, "failed to read left: trailing data after offset 3")
+
+	_, err = diff.New().CompareStreams(strings.NewReader(" [1] \n"), strings.NewReader(`[1]`))
+	assert.NoError(t, err)
+}
+
+// itemsReader generates JSON document with many items and tracks how far it is read ahead of other document.
+type itemsReader struct {
+	items, next int
+	buf         []byte
+	last        string
+	read        int
+	other       *itemsReader
+	maxLead     int
+}
+
+func (r *itemsReader) Read(p []byte) (int, error) {
+	for len(r.buf) < len(p) && r.next <= r.items {
+		switch {
+		case r.next == 0:
+			r.buf = append(r.buf, `{"items":[`...)
+		case r.next == r.items:
+			r.buf = append(r.buf, `]}`...)
+		default:
+			if r.next > 1 {
+				r.buf = append(r.buf, ',')
+			}
+
+			name := fmt.Sprintf("item %d", r.next)
+			if r.next == r.items-1 && r.last != "" {
+				name = r.last
+			}
+
+			r.buf = append(r.buf, fmt.Sprintf(`{"id":%d,"name":%q,"tags":["a","b"]}`, r.next, name)...)
+		}
+
+		r.next++
+	}
+
+	if len(r.buf) == 0 {
+		return 0, io.EOF
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[:copy(r.buf, r.buf[n:])]
+
+	r.read += n
+
+	if lead := r.read - r.other.read; lead > r.maxLead {
+		r.maxLead = lead
+	}
+
+	return n, nil
+}
+
+func TestDiffer_CompareStreams_boundedMemory(t *testing.T) {
+	left := &itemsReader{items: 100000}
+	right := &itemsReader{items: 100000, last: "changed"}
+	left.other, right.other = right, left
+
+	d, err := diff.New().CompareStreams(left, right)
+	require.NoError(t, err)
+
+	s, err := diff.NewPathSummaryFormatter(diff.PathSummaryFormatterConfig{}).Format(d)
+	require.NoError(t, err)
+	assert.Equal(t, "/items/99998/name: \"item 99999\" -> \"changed\"\n", s)
+
+	// Documents are read in lockstep, so only a small part of each document is buffered at a time,
+	// decoding of a whole document would read it entirely ahead of the other one.
+	assert.Greater(t, left.read, 4<<20)
+	assert.Less(t, left.maxLead, 64<<10)
+	assert.Less(t, right.maxLead, 64<<10)
+}
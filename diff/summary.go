@@ -0,0 +1,95 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// NewPathSummaryFormatter creates a new PathSummaryFormatter instance with the specified configuration.
+func NewPathSummaryFormatter(config PathSummaryFormatterConfig) *PathSummaryFormatter {
+	return &PathSummaryFormatter{
+		config: config,
+	}
+}
+
+// PathSummaryFormatter renders differences as one line per change prefixed with JSON Pointer, e.g.
+//
+//	/data/users/3/email: "a@x" -> "b@x"
+//	/data/users/4: added {"email":"c@x","id":5}
+//	/data/tags/0: removed "admin"
+//	/data/items/0: moved from /data/items/2
+type PathSummaryFormatter struct {
+	config PathSummaryFormatterConfig
+}
+
+// PathSummaryFormatterConfig specifies configuration options for path summary formatting.
+type PathSummaryFormatterConfig struct {
+	// MaxValueLength is a maximum length of rendered value, longer objects and arrays are
+	// summarized with number of elements and longer strings are truncated, default 60.
+	MaxValueLength int
+}
+
+// Format formats the differences as path summary.
+func (f *PathSummaryFormatter) Format(diff Diff) (result string, err error) {
+	return f.FormatChanges(Changes(diff.Deltas())), nil
+}
+
+// FormatChanges formats the changes as path summary.
+func (f *PathSummaryFormatter) FormatChanges(changes []Change) string {
+	var res strings.Builder
+
+	for _, c := range changes {
+		path := c.Path
+		if path == "" {
+			path = "(root)"
+		}
+
+		switch c.Type {
+		case ChangeAdded:
+			fmt.Fprintf(&res, "%s: added %s\n", path, f.summary(c.NewValue))
+		case ChangeDeleted:
+			fmt.Fprintf(&res, "%s: removed %s\n", path, f.summary(c.OldValue))
+		case ChangeMoved:
+			fmt.Fprintf(&res, "%s: moved from %s\n", path, c.From)
+		default:
+			fmt.Fprintf(&res, "%s: %s -> %s\n", path, f.summary(c.OldValue), f.summary(c.NewValue))
+		}
+	}
+
+	return res.String()
+}
+
+// summary renders value as compact JSON, long values are shortened.
+func (f *PathSummaryFormatter) summary(v interface{}) string {
+	maxLength := f.config.MaxValueLength
+	if maxLength <= 0 {
+		maxLength = 60
+	}
+
+	b := bytes.NewBuffer(nil)
+	enc := json.NewEncoder(b)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(v); err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+
+	s := strings.TrimSuffix(b.String(), "\n")
+	if utf8.RuneCountInString(s) <= maxLength {
+		return s
+	}
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return fmt.Sprintf("{...} (%d keys)", len(v))
+	case []interface{}:
+		return fmt.Sprintf("[...] (%d items)", len(v))
+	case string:
+		return string([]rune(s)[:maxLength-1]) + `..." (` + fmt.Sprint(utf8.RuneCountInString(v)) + ` chars)`
+	default:
+		return s
+	}
+}
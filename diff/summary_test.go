@@ -0,0 +1,40 @@
+package diff_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson/diff"
+)
+
+func TestPathSummaryFormatter_Format(t *testing.T) {
+	var left, right interface{}
+
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"users":[{"id":1,"email":"a@x"},{"id":2,"email":"b@x"}],
+		"note":"`+strings.Repeat("a", 100)+`","tags":["a","b"]
+	}`), &left))
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"users":[{"id":1,"email":"a@x"},{"id":2,"email":"c@x"},{"id":3,"email":"d@x","name":"Dan","roles":["admin","editor"]}],
+		"tags":["b"]
+	}`), &right))
+
+	s, err := diff.NewPathSummaryFormatter(diff.PathSummaryFormatterConfig{MaxValueLength: 40}).
+		Format(diff.New().CompareValues(left, right))
+	require.NoError(t, err)
+	assert.Equal(t, `/note: removed "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa..." (100 chars)
+/tags/0: removed "a"
+/users/1/email: "b@x" -> "c@x"
+/users/2: added {...} (4 keys)
+`, s)
+}
+
+func TestPathSummaryFormatter_Format_root(t *testing.T) {
+	s, err := diff.NewPathSummaryFormatter(diff.PathSummaryFormatterConfig{}).
+		Format(diff.New().CompareValues("foo", 123.0))
+	require.NoError(t, err)
+	assert.Equal(t, "(root): \"foo\" -> 123\n", s)
+}
@@ -0,0 +1,200 @@
+package diff
+
+import (
+	"math"
+	"reflect"
+)
+
+// compareArraysUnordered compares arrays as multisets, so that only missing, extra or changed items are reported.
+//
+// Identical items are paired first, rest of items are paired to maximize number of equal pairs
+// (e.g. numbers within tolerance) and then total similarity. Unpaired left items are deleted at their
+// left positions, unpaired right items are added and changes of paired items are located at their right
+// positions, order of items is not reported.
+func (differ *Differ) compareArraysUnordered(left, right []interface{}) []Delta {
+	var (
+		pairedLeft  = make([]bool, len(left))
+		pairedRight = make([]bool, len(right))
+		changed     = make(map[int]Delta) // Changes of paired items by left index.
+		freeLeft    []int
+		freeRight   []int
+	)
+
+	for li, l := range left {
+		for ri, r := range right {
+			if !pairedRight[ri] && reflect.DeepEqual(l, r) {
+				pairedLeft[li] = true
+				pairedRight[ri] = true
+
+				break
+			}
+		}
+
+		if !pairedLeft[li] {
+			freeLeft = append(freeLeft, li)
+		}
+	}
+
+	for ri := range right {
+		if !pairedRight[ri] {
+			freeRight = append(freeRight, ri)
+		}
+	}
+
+	for _, p := range differ.pairSimilar(left, right, freeLeft, freeRight) {
+		pairedLeft[p.left] = true
+		pairedRight[p.right] = true
+
+		if !p.same {
+			setChangedFrom(p.delta, Index(p.left))
+			changed[p.left] = p.delta
+		}
+	}
+
+	deltas := make([]Delta, 0)
+
+	for li := range left {
+		if !pairedLeft[li] {
+			deltas = append(deltas, NewDeleted(Index(li), left[li]))
+		}
+	}
+
+	for li := range left {
+		if d, ok := changed[li]; ok {
+			deltas = append(deltas, d)
+		}
+	}
+
+	for ri := range right {
+		if !pairedRight[ri] {
+			deltas = append(deltas, NewAdded(Index(ri), right[ri]))
+		}
+	}
+
+	return deltas
+}
+
+// pairSimilar pairs free items of arrays with the best total weight, equal items weigh more than any
+// number of different items together, different items weigh their similarity.
+func (differ *Differ) pairSimilar(left, right []interface{}, freeLeft, freeRight []int) []arrayPair {
+	if len(freeLeft) == 0 || len(freeRight) == 0 {
+		return nil
+	}
+
+	equalWeight := float64(len(freeLeft) + len(freeRight))
+	candidates := make([][]arrayPair, len(freeLeft))
+	cost := make([][]float64, len(freeLeft))
+
+	for x, li := range freeLeft {
+		candidates[x] = make([]arrayPair, len(freeRight))
+		cost[x] = make([]float64, len(freeRight))
+
+		for y, ri := range freeRight {
+			same, delta := differ.compareValues(Index(ri), left[li], right[ri])
+			candidates[x][y] = arrayPair{left: li, right: ri, same: same, delta: delta}
+
+			weight := equalWeight
+			if !same {
+				weight = delta.Similarity()
+			}
+
+			cost[x][y] = -weight
+		}
+	}
+
+	assigned := assign(cost)
+	pairs := make([]arrayPair, 0, len(assigned))
+
+	for _, xy := range assigned {
+		pairs = append(pairs, candidates[xy[0]][xy[1]])
+	}
+
+	return pairs
+}
+
+// assign returns row and column pairs of cost matrix with the minimal total cost,
+// number of pairs is the lesser of numbers of rows and columns.
+func assign(cost [][]float64) [][2]int {
+	if len(cost) > len(cost[0]) {
+		transposed := make([][]float64, len(cost[0]))
+
+		for y := range transposed {
+			transposed[y] = make([]float64, len(cost))
+
+			for x := range cost {
+				transposed[y][x] = cost[x][y]
+			}
+		}
+
+		pairs := assign(transposed)
+		for i, p := range pairs {
+			pairs[i] = [2]int{p[1], p[0]}
+		}
+
+		return pairs
+	}
+
+	// Hungarian algorithm with potentials, rows and columns are 1-based, column 0 is a fictive one.
+	n, m := len(cost), len(cost[0])
+	u := make([]float64, n+1)
+	v := make([]float64, m+1)
+	row := make([]int, m+1) // Row assigned to column.
+	way := make([]int, m+1)
+
+	for i := 1; i <= n; i++ {
+		row[0] = i
+		j0 := 0
+		minV := make([]float64, m+1)
+		used := make([]bool, m+1)
+
+		for j := range minV {
+			minV[j] = math.Inf(1)
+		}
+
+		for row[j0] != 0 {
+			used[j0] = true
+			i0, delta, j1 := row[j0], math.Inf(1), 0
+
+			for j := 1; j <= m; j++ {
+				if used[j] {
+					continue
+				}
+
+				if cur := cost[i0-1][j-1] - u[i0] - v[j]; cur < minV[j] {
+					minV[j], way[j] = cur, j0
+				}
+
+				if minV[j] < delta {
+					delta, j1 = minV[j], j
+				}
+			}
+
+			for j := 0; j <= m; j++ {
+				if used[j] {
+					u[row[j]] += delta
+					v[j] -= delta
+				} else {
+					minV[j] -= delta
+				}
+			}
+
+			j0 = j1
+		}
+
+		for j0 != 0 {
+			j1 := way[j0]
+			row[j0] = row[j1]
+			j0 = j1
+		}
+	}
+
+	pairs := make([][2]int, 0, n)
+
+	for j := 1; j <= m; j++ {
+		if row[j] != 0 {
+			pairs = append(pairs, [2]int{row[j] - 1, j - 1})
+		}
+	}
+
+	return pairs
+}
@@ -0,0 +1,82 @@
+package diff_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson/diff"
+)
+
+func TestDiffer_CompareValues_unorderedArrays(t *testing.T) {
+	var left, right interface{}
+
+	leftJSON := `{"tags":["a",{"id":1,"v":"x"},"b","c"]}`
+	rightJSON := `{"tags":["b","d",{"id":1,"v":"y"},"a"]}`
+
+	require.NoError(t, json.Unmarshal([]byte(leftJSON), &left))
+	require.NoError(t, json.Unmarshal([]byte(rightJSON), &right))
+
+	differ := diff.NewWithConfig(diff.DifferConfig{UnorderedArrays: true})
+	d := differ.CompareValues(left, right)
+
+	// Changed items are located at their positions in right array.
+	s, err := diff.NewPathSummaryFormatter(diff.PathSummaryFormatterConfig{}).Format(d)
+	require.NoError(t, err)
+	assert.Equal(t, `/tags/2/v: "x" -> "y"
+/tags/1: "c" -> "d"
+`, s)
+
+	p, err := diff.NewJSONPatchFormatter(left).Format(d)
+	require.NoError(t, err)
+
+	patched, err := diff.ApplyJSONPatch([]byte(leftJSON), []byte(p))
+	require.NoError(t, err)
+
+	var patchedValue interface{}
+
+	require.NoError(t, json.Unmarshal(patched, &patchedValue))
+	assert.False(t, differ.CompareValues(patchedValue, right).Modified(), string(patched))
+
+	// Order of items is not a difference.
+	require.NoError(t, json.Unmarshal([]byte(`{"tags":["c",{"v":"x","id":1},"a","b"]}`), &right))
+	assert.False(t, differ.CompareValues(left, right).Modified())
+
+	// Paired items that are out of order are not reported.
+	require.NoError(t, json.Unmarshal([]byte(`[1,2,3]`), &left))
+	require.NoError(t, json.Unmarshal([]byte(`[3,2,1,4]`), &right))
+
+	s, err = diff.NewPathSummaryFormatter(diff.PathSummaryFormatterConfig{}).Format(differ.CompareValues(left, right))
+	require.NoError(t, err)
+	assert.Equal(t, "/3: added 4\n", s)
+}
+
+func TestDiffer_CompareValues_unorderedArraysBestMatch(t *testing.T) {
+	differ := diff.NewWithConfig(diff.DifferConfig{UnorderedArrays: true, NumberTolerance: 0.05})
+
+	// First fit would pair 1.0 with 1.04 and leave 1.05 and 1.0 different.
+	for _, tc := range [][2]string{
+		{`[1.0, 1.05]`, `[1.04, 1.0]`},
+		{`[1.05, 1.0]`, `[1.04, 1.0]`},
+		{`[1.0, 1.05]`, `[1.0, 1.04]`},
+	} {
+		var left, right interface{}
+
+		require.NoError(t, json.Unmarshal([]byte(tc[0]), &left))
+		require.NoError(t, json.Unmarshal([]byte(tc[1]), &right))
+
+		assert.False(t, differ.CompareValues(left, right).Modified(), tc)
+	}
+
+	var left, right interface{}
+
+	require.NoError(t, json.Unmarshal([]byte(`["abcdef", "uvwxyz"]`), &left))
+	require.NoError(t, json.Unmarshal([]byte(`["uvwxyZ", "abcdeF"]`), &right))
+
+	s, err := diff.NewPathSummaryFormatter(diff.PathSummaryFormatterConfig{}).Format(differ.CompareValues(left, right))
+	require.NoError(t, err)
+	assert.Equal(t, `/1: "abcdef" -> "abcdeF"
+/0: "uvwxyz" -> "uvwxyZ"
+`, s)
+}
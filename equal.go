@@ -4,7 +4,6 @@ package assertjson
 import (
 	"strings"
 
-	"github.com/bool64/shared"
 	"github.com/stretchr/testify/assert"
 	"github.com/swaggest/assertjson/diff"
 )
@@ -14,12 +13,51 @@ type Comparer struct {
 	// IgnoreDiff is a value in expected document to ignore difference with actual document.
 	IgnoreDiff string
 
-	// Vars keeps state of found variables.
-	Vars *shared.Vars
+	// IgnorePaths is a list of locations to ignore difference at, see diff.ParseSelector for syntax.
+	// For example, "/items/*/id" ignores id of every item and "**/updatedAt" ignores updatedAt at any depth.
+	// Malformed selector fails comparison with diff.ErrInvalidSelector.
+	IgnorePaths []string
+
+	// Placeholders is a registry of named placeholders in expected document, e.g. "<any-number>" or "<uuid>".
+	Placeholders *Placeholders
+
+	// RegexpPrefix and RegexpSuffix enclose a regular expression in expected string value,
+	// e.g. "<regexp:^req-[0-9a-f]{8}$>" with prefix "<regexp:" and suffix ">".
+	// Regexp placeholders are disabled if RegexpPrefix is empty.
+	RegexpPrefix string
+	RegexpSuffix string
+
+	// Vars keeps state of found variables, for example *shared.Vars.
+	Vars VarStore
+
+	// VarTemplates enables references to variables within strings as "${name}", e.g. "/users/${id}/avatar".
+	VarTemplates bool
+
+	// VarRecognizer checks if expected string value is a variable reference, e.g. "{{id}}" or ":id".
+	// Vars.IsVar is used if VarRecognizer is nil. References within strings as "${name}" are only
+	// resolved if "$name" is recognized as a variable.
+	VarRecognizer func(s string) bool
+
+	// ArrayMatching controls how expected arrays are matched with actual arrays in Matches, default exact.
+	// Difference of matched arrays is computed against aligned document, where actual array is replaced with
+	// matched expected items, so that Result only has unmatched expected items at their expected positions.
+	ArrayMatching ArrayMatching
+
+	// Strict rejects actual payload with duplicate object keys, trailing data after JSON value or invalid UTF-8.
+	Strict bool
+
+	// DifferConfig controls comparison configuration, e.g. number tolerance.
+	DifferConfig diff.DifferConfig
 
 	// FormatterConfig controls diff formatter configuration.
 	FormatterConfig diff.ASCIIFormatterConfig
 
+	// DiffFormat selects presentation of difference, default DiffASCII.
+	DiffFormat DiffFormat
+
+	// ColumnWidth is a maximum width of a column in DiffSideBySide format, default 60.
+	ColumnWidth int
+
 	// KeepFullDiff shows full diff in error message.
 	KeepFullDiff bool
 
@@ -30,13 +68,26 @@ type Comparer struct {
 	// DiffSurroundingLines is a number of lines to add before and after diff line, default 5.
 	// Ignored if KeepFullDiff is true.
 	DiffSurroundingLines int
+
+	// UpdateGolden enables rewriting of golden files with actual payload in EqualFile and EqualMarshalFile.
+	// Can also be enabled with ASSERTJSON_UPDATE_GOLDEN=1 environment variable.
+	UpdateGolden bool
 }
 
 // IgnoreDiff is a marker to ignore difference in JSON.
 const IgnoreDiff = "<ignore-diff>"
 
+// Default enclosing of regexp placeholder, e.g. "<regexp:^req-[0-9a-f]{8}$>".
+const (
+	RegexpPrefix = "<regexp:"
+	RegexpSuffix = ">"
+)
+
 var defaultComparer = Comparer{
-	IgnoreDiff: IgnoreDiff,
+	IgnoreDiff:   IgnoreDiff,
+	Placeholders: DefaultPlaceholders,
+	RegexpPrefix: RegexpPrefix,
+	RegexpSuffix: RegexpSuffix,
 }
 
 // TestingT is an interface wrapper around *testing.T.
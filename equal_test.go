@@ -3,6 +3,7 @@ package assertjson_test
 import (
 	"io/ioutil"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/bool64/shared"
@@ -59,9 +60,9 @@ func TestEquals_message(t *testing.T) {
 		assert.Equal(t, "\n%s", format)
 		assert.Len(t, args, 1)
 
-		assert.Equal(t, `	Error Trace:	equal.go:82
-	            				equal.go:57
-	            				equal_test.go:58
+		assert.Equal(t, `	Error Trace:	equal.go:133
+	            				equal.go:108
+	            				equal_test.go:59
 	Error:      	Not equal:
 	            	 {
 	            	   "createdAt": "<ignore-diff>",
@@ -136,6 +137,103 @@ func TestComparer_Equal_IgnoreDiff(t *testing.T) {
 	}, c.Equal)
 }
 
+func TestComparer_Equal_IgnorePaths(t *testing.T) {
+	c := assertjson.Comparer{
+		IgnorePaths: []string{"/items/*/id", "**/updatedAt", "$.meta.trace", "/a~1b"},
+	}
+
+	run(t, []testcase{
+		{`{"items": [{"id": 1, "v": 1}, {"id": 2, "v": 2}]}`, `{"items": [{"id": 3, "v": 1}, {"id": 4, "v": 2}]}`, true},
+		{`{"items": [{"id": 1, "v": 1}]}`, `{"items": [{"id": 3, "v": 2}]}`, false},
+		{`{"items": [{"v": 1}]}`, `{"items": [{"id": 3, "v": 1}]}`, true},
+		{`{"items": [{"id": 1, "v": 1}]}`, `{"items": [{"v": 1}]}`, true},
+		{`{"updatedAt": 1, "a": {"b": [{"updatedAt": 2}]}}`, `{"updatedAt": 3, "a": {"b": [{"updatedAt": 4}]}}`, true},
+		{`{"meta": {"trace": "abc", "v": 1}}`, `{"meta": {"trace": {"id": 1}, "v": 1}}`, true},
+		{`{"meta": {"trace": "abc", "v": 1}}`, `{"meta": {"trace": "abc", "v": 2}}`, false},
+		{`{"a/b": 1, "ab": 1}`, `{"a/b": 2, "ab": 1}`, true},
+	}, c.Equal)
+}
+
+func TestComparer_Equal_numbers(t *testing.T) {
+	run(t, []testcase{
+		{`{"a": 1.0}`, `{"a": 1}`, false},
+		{`1e2`, `100`, false},
+	}, assertjson.Equal)
+
+	c := assertjson.Comparer{}
+	c.DifferConfig.SemanticNumbers = true
+
+	run(t, []testcase{
+		{`{"a": 1.0, "b": [1e2, 2.50]}`, `{"a": 1, "b": [100, 2.5]}`, true},
+		{`1e2`, `100`, true},
+		{`[1.0, 2, 3]`, `[1, 2.0, 3.00]`, true},
+		{`{"a": 17294094973108486143}`, `{"a": 17294094973108486143.0}`, true},
+		{`{"a": 17294094973108486143}`, `{"a": 17294094973108486144}`, false},
+		{`{"a": 0.3}`, `{"a": 0.30000000000000004}`, false},
+		{`{"a": 1}`, `{"a": "1"}`, false},
+	}, c.Equal)
+
+	c.DifferConfig.NumberTolerance = 1e-9
+
+	run(t, []testcase{
+		{`{"a": 0.3}`, `{"a": 0.30000000000000004}`, true},
+		{`[0.3, 1]`, `[0.30000000000000004, 1]`, true},
+		{`0.3`, `0.30000000000000004`, true},
+		{`{"a": 0.3}`, `{"a": 0.31}`, false},
+	}, c.Equal)
+
+	c.DifferConfig.NumberTolerance = 0
+	c.DifferConfig.NumberRelativeTolerance = 0.01
+
+	run(t, []testcase{
+		{`{"a": 1000}`, `{"a": 1009}`, true},
+		{`{"a": -1000}`, `{"a": -1011}`, false},
+		{`{"a": 0}`, `{"a": 0.001}`, false},
+	}, c.Equal)
+}
+
+func TestComparer_Equal_unorderedArrays(t *testing.T) {
+	c := assertjson.Comparer{}
+	c.DifferConfig.UnorderedArrayPaths = []string{"/tags", "/items/*/perms"}
+
+	run(t, []testcase{
+		{`{"tags": ["a", "b", "c"]}`, `{"tags": ["c", "a", "b"]}`, true},
+		{`{"tags": ["a", "b", "a"]}`, `{"tags": ["b", "a", "a"]}`, true},
+		{`{"tags": ["a", "b", "a"]}`, `{"tags": ["b", "a", "b"]}`, false},
+		{`{"items": [{"perms": [1, 2]}, {"perms": [3, 4]}]}`, `{"items": [{"perms": [2, 1]}, {"perms": [4, 3]}]}`, true},
+		{`{"other": [1, 2]}`, `{"other": [2, 1]}`, false},
+	}, c.Equal)
+
+	err := c.FailNotEqual(
+		[]byte(`{"tags": ["a", {"id": 1, "v": "x"}, "b", "c"]}`),
+		[]byte(`{"tags": ["b", "d", {"id": 1, "v": "y"}, "a"]}`),
+	)
+	// Order of items is not a difference, changed items are shown at positions of expected array.
+	assert.EqualError(t, err, `not equal:
+ {
+   "tags": [
+     "a",
+     {
+       "id": 1,
+-      "v": "x"
++      "v": "y"
+     },
+     "b",
+-    "c"
++    "d"
+   ]
+ }
+`)
+
+	c = assertjson.Comparer{}
+	c.DifferConfig.UnorderedArrays = true
+
+	run(t, []testcase{
+		{`[[1, 2], [3, 4]]`, `[[4, 3], [2, 1]]`, true},
+		{`[1, 2]`, `[2, 1, 3]`, false},
+	}, c.Equal)
+}
+
 func TestEqual(t *testing.T) {
 	run(t, []testcase{
 		{`{`, `{}`, false},
@@ -176,6 +274,131 @@ func TestFailNotEqualMarshal(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestFailNotEqual_root(t *testing.T) {
+	for _, tc := range []struct {
+		exp, act string
+		diff     string
+	}{
+		{`123`, `321`, "-123\n+321\n"},
+		{`"abc"`, `null`, "-\"abc\"\n+null\n"},
+		{`null`, `false`, "-null\n+false\n"},
+		{`{"a":1}`, `[1]`, "-{\n-  \"a\": 1\n-}\n+[\n+  1\n+]\n"},
+		{`[]`, `{}`, "-[\n-]\n+{\n+}\n"},
+		{`"a long text that is compared with text diff"`, `"a long text that is compared with another diff"`,
+			"-\"a long text that is compared with [-text-] diff\"\n+\"a long text that is compared with {+another+} diff\"\n"},
+	} {
+		assert.EqualError(t, assertjson.FailNotEqual([]byte(tc.exp), []byte(tc.act)), "not equal:\n"+tc.diff)
+	}
+
+	assert.NoError(t, assertjson.FailNotEqual([]byte(`"<any-number>"`), []byte(`1.5`)))
+	assert.EqualError(t, assertjson.FailNotEqual([]byte(`"<any-number>"`), []byte(`"1.5"`)),
+		"not equal:\n-\"<any-number>\"\n+\"1.5\"\nexpected number, got string\n")
+}
+
+func TestComparer_FailNotEqual_textDiff(t *testing.T) {
+	exp := []byte(`{"html": "<div><p>Hello, World!</p></div>", "a": "short"}`)
+	act := []byte(`{"html": "<div><p>Hello, world.</p></div>", "a": "shirt"}`)
+
+	c := assertjson.Comparer{}
+	c.DifferConfig.TextDiffMinimumLength = 5
+
+	assert.EqualError(t, c.FailNotEqual(exp, act), `not equal:
+ {
+-  "a": "sh[-o-]rt",
++  "a": "sh{+i+}rt",
+-  "html": "<div><p>Hello, [-W-]orld[-!-]</p></div>"
++  "html": "<div><p>Hello, {+w+}orld{+.+}</p></div>"
+ }
+`)
+
+	c.FormatterConfig.Coloring = true
+	c.DifferConfig.TextDiffMinimumLength = 10
+
+	assert.EqualError(t, c.FailNotEqual(exp, act), "not equal:\n"+
+		" {\n"+
+		"\x1b[30;41m-  \"a\": \"short\",\x1b[0m\n"+
+		"\x1b[30;42m+  \"a\": \"shirt\",\x1b[0m\n"+
+		"\x1b[30;41m-  \"html\": \"<div><p>Hello, \x1b[30;101mW\x1b[0m\x1b[30;41morld"+
+		"\x1b[30;101m!\x1b[0m\x1b[30;41m</p></div>\"\x1b[0m\n"+
+		"\x1b[30;42m+  \"html\": \"<div><p>Hello, \x1b[30;102mw\x1b[0m\x1b[30;42morld"+
+		"\x1b[30;102m.\x1b[0m\x1b[30;42m</p></div>\"\x1b[0m\n"+
+		" }\n")
+
+	c.FormatterConfig.Coloring = false
+	c.DifferConfig.TextDiffMinimumLength = -1
+
+	assert.EqualError(t, c.FailNotEqual(exp, act), `not equal:
+ {
+-  "a": "short",
++  "a": "shirt",
+-  "html": "<div><p>Hello, World!</p></div>"
++  "html": "<div><p>Hello, world.</p></div>"
+ }
+`)
+}
+
+func TestComparer_FailNotEqual_sideBySide(t *testing.T) {
+	c := assertjson.Comparer{DiffFormat: assertjson.DiffSideBySide, ColumnWidth: 24}
+
+	err := c.FailNotEqual(
+		[]byte(`{"a": 1, "b": [1, 2, 3], "c": {"d": "a long string value"}, "e": true}`),
+		[]byte(`{"a": 2, "b": [1, 3], "c": {"d": "another long string value"}, "e": true, "f": [4]}`),
+	)
+	assert.EqualError(t, err, `not equal:
+{                          {
+  "a": 1,                |   "a": 2,
+  "b": [                     "b": [
+    1,                         1,
+    2,                   <
+    3                          3
+  ],                         ],
+  "c": {                     "c": {
+    "d": "a long string  |     "d": "another long s
+value"                   | tring value"
+  },                         },
+  "e": true                  "e": true
+                         >   "f": [
+                         >     4
+                         >   ]
+}                          }
+`)
+}
+
+func TestComparer_FailNotEqual_pathSummary(t *testing.T) {
+	c := assertjson.Comparer{DiffFormat: assertjson.DiffPathSummary, Placeholders: assertjson.DefaultPlaceholders}
+
+	err := c.FailNotEqual(
+		[]byte(`{"data": {"users": [{"id": 1, "email": "a@x"}, {"id": 2, "email": "b@x"}], "total": 2}, `+
+			`"meta": {"version": "<any-number>", "cursor": "abc"}, "tags": ["a", "b"]}`),
+		[]byte(`{"data": {"users": [{"id": 1, "email": "a@x"}, {"id": 2, "email": "c@x"}, `+
+			`{"id": 3, "email": "d@x", "name": "Dan", "roles": ["admin", "editor", "viewer"], "active": true}]}, `+
+			`"meta": {"version": "1"}, "tags": ["b"]}`),
+	)
+	assert.EqualError(t, err, `not equal:
+/data/total: removed 2
+/data/users/1/email: "b@x" -> "c@x"
+/data/users/2: added {...} (5 keys)
+/meta/cursor: removed "abc"
+/meta/version: "<any-number>" -> "1"
+/tags/0: removed "a"
+/meta/version: expected number, got string
+`)
+
+	assert.EqualError(t, c.FailNotEqual([]byte(`[1]`), []byte(`"`+strings.Repeat("a", 100)+`"`)), `not equal:
+(root): [1] -> "`+strings.Repeat("a", 58)+`..." (100 chars)
+`)
+}
+
+func TestComparer_FailNotEqual_sideBySideRoot(t *testing.T) {
+	c := assertjson.Comparer{DiffFormat: assertjson.DiffSideBySide, ColumnWidth: 10}
+
+	assert.EqualError(t, c.FailNotEqual([]byte(`[1]`), []byte(`{"a":1}`)), `not equal:
+[          | {
+  1        |   "a": 1
+]          | }
+`)
+}
+
 func TestComparer_Equal_vars(t *testing.T) {
 	v := &shared.Vars{}
 	v.Set("$varB", []int{1, 2, 3})
@@ -206,7 +429,7 @@ func TestComparer_Equal_vars(t *testing.T) {
 	assert.EqualError(t, err, `not equal:
  {
    "a": 1.23,
-   "b": [
+   "b": [ // $varB
      1,
      2,
 -    3
@@ -305,8 +528,8 @@ func TestComparer_Equal_long(t *testing.T) {
        },
        "support": {
          "issues": "https://github.com/swaggest/json-diff/issues",
--        "source": "https://github.com/swaggest/json-diff/tree/v3.8.1"
-+        "source": "https://github.com/swaggest/json-diff/tree/v3.8.2"
+-        "source": "https://github.com/swaggest/json-diff/tree/v3.8.[-1-]"
++        "source": "https://github.com/swaggest/json-diff/tree/v3.8.{+2+}"
        },
        "time": "2020-09-25T17:47:07+00:00",
 -      "type": "library",
@@ -321,7 +544,8 @@ func TestComparer_Equal_long(t *testing.T) {
          "url": "https://api.github.com/repos/phpDocumentor/ReflectionDocBlock/zipball/bf329f6c1aadea3299f08ee804682b7c45b326a2"
        },
        "license": [
-+        "BSD3"
++        "BSD3",
+         "MIT",
 +        "Apache 2.0"
        ],
        "name": "phpdocumentor/reflection-docblock",
@@ -346,7 +570,7 @@ func TestComparer_Equal_long(t *testing.T) {
          ]
        },
 -      "description": "FilterIterator implementation that filters files based on a list of suffixes.",
-+      "description": "FilterIterator implementation that filterers files based on a list of suffixes.",
++      "description": "FilterIterator implementation that filter{+er+}s files based on a list of suffixes.",
        "dist": {
          "reference": "730b01bc3e867237eaac355e06a36b85dd93a8b4",
          "shasum": "",
@@ -357,8 +581,8 @@ func TestComparer_Equal_long(t *testing.T) {
        },
        "support": {
          "issues": "https://github.com/webmozarts/assert/issues",
--        "source": "https://github.com/webmozarts/assert/tree/1.9.1"
-+        "source": "https://github.com/webmozarts/assert/tree/1.9.2"
+-        "source": "https://github.com/webmozarts/assert/tree/1.9.[-1-]"
++        "source": "https://github.com/webmozarts/assert/tree/1.9.{+2+}"
        },
        "time": "2020-07-08T17:02:28+00:00",
        "type": "library",
@@ -376,3 +600,199 @@ func TestComparer_Equal_long(t *testing.T) {
    },
 ...`)
 }
+
+func TestComparer_FailNotEqual_varsTyped(t *testing.T) {
+	v := &shared.Vars{}
+	v.Set("$id", 1)
+	v.Set("$idx", "2")
+
+	c := assertjson.Comparer{Vars: v}
+
+	assert.NoError(t, c.FailNotEqual(
+		[]byte(`{"id":"$id","idx":"$idx","text":"see \"$id\"","ref":"#/defs/$id","list":["$id",{"idx":"$idx"}]}`),
+		[]byte(`{"id":1,"idx":"2","text":"see \"$id\"","ref":"#/defs/$id","list":[1,{"idx":"2"}]}`),
+	))
+
+	assert.EqualError(t, c.FailNotEqual([]byte(`{"id":"$id","idx":"$idx"}`), []byte(`{"id":"1","idx":2}`)), `not equal:
+ {
+-  "id": 1, // $id
++  "id": "1",
+-  "idx": "2" // $idx
++  "idx": 2
+ }
+`)
+
+	assert.EqualError(t, c.FailNotEqual([]byte(`{"a":[{"$id":1}]}`), []byte(`{"a":[{"1":1}]}`)),
+		"variable $id is used as object key at /a/0/$id, only values are supported")
+
+	// Keys are checked regardless of values of variables.
+	assert.EqualError(t, c.FailNotEqual([]byte(`{"a":{"$unknown":1}}`), []byte(`{"a":{"$unknown":1}}`)),
+		"variable $unknown is used as object key at /a/$unknown, only values are supported")
+}
+
+func TestComparer_FailNotEqual_varsTemplate(t *testing.T) {
+	v := &shared.Vars{}
+	v.Set("$host", "api")
+	v.Set("$port", 8080)
+
+	c := assertjson.Comparer{Vars: v, VarTemplates: true}
+
+	// Known variables are substituted, unknown variables are collected from actual value.
+	assert.NoError(t, c.FailNotEqual(
+		[]byte(`{"url":"https://${host}:${port}/users/${userId}/avatar","next":"${userId}-${page}","self":"$$${userId}"}`),
+		[]byte(`{"url":"https://api:8080/users/123/avatar","next":"123-2","self":"$$123"}`),
+	))
+
+	userID, found := v.Get("$userId")
+	assert.True(t, found)
+	assert.Equal(t, int64(123), userID)
+
+	page, found := v.Get("$page")
+	assert.True(t, found)
+	assert.Equal(t, int64(2), page)
+
+	assert.EqualError(t, c.FailNotEqual(
+		[]byte(`{"url":"https://${host}/users/${userId}","copy":"${other}/${other}","n":"${x}"}`),
+		[]byte(`{"url":"https://api/users/124","copy":"a/b","n":1}`),
+	), `not equal:
+ {
+-  "copy": "${other}/${other}",
++  "copy": "a/b",
+-  "n": "${x}",
++  "n": 1,
+-  "url": "https://api/users/123" // $host, $userId
++  "url": "https://api/users/124"
+ }
+/copy: "a/b" does not match "${other}/${other}", $other is both "a" and "b"
+/n: expected string matching "${x}", got number
+`)
+
+	_, found = v.Get("$other")
+	assert.False(t, found)
+}
+
+func TestComparer_FailNotEqual_varsConflict(t *testing.T) {
+	v := &shared.Vars{}
+	c := assertjson.Comparer{Vars: v, VarTemplates: true}
+
+	assert.EqualError(t, c.FailNotEqual(
+		[]byte(`{"a":"$id","b":"$id","c":"/users/${id}"}`),
+		[]byte(`{"a":1,"b":2,"c":"/users/3"}`),
+	), `not equal:
+ {
+   "a": "$id",
+-  "b": "$id",
++  "b": 2,
+-  "c": "/users/${id}"
++  "c": "/users/3"
+ }
+variable $id bound to 1 but found 2 at /b
+variable $id bound to 1 but found 3 at /c
+collected variables:
+$id: 1
+`)
+
+	id, found := v.Get("$id")
+	assert.True(t, found)
+	assert.EqualValues(t, 1, id)
+
+	assert.NoError(t, c.FailNotEqual([]byte(`{"a":"$n","b":["$n"]}`), []byte(`{"a":"x","b":["x"]}`)))
+}
+
+func TestComparer_FailNotEqual_varsTemplateTyped(t *testing.T) {
+	v := &shared.Vars{}
+	c := assertjson.Comparer{Vars: v, VarTemplates: true}
+
+	// Integer captured from a template matches number and the same digits in another template.
+	assert.NoError(t, c.FailNotEqual(
+		[]byte(`{"self":"/users/${id}","id":"$id","next":"/users/${id}/next","name":"${name}","title":"$name"}`),
+		[]byte(`{"self":"/users/123","id":123,"next":"/users/123/next","name":"007","title":"007"}`),
+	))
+
+	id, _ := v.Get("$id")
+	assert.Equal(t, int64(123), id)
+
+	name, _ := v.Get("$name")
+	assert.Equal(t, "007", name)
+
+	assert.EqualError(t, c.FailNotEqual([]byte(`{"id":"$id"}`), []byte(`{"id":"123"}`)), `not equal:
+ {
+-  "id": 123 // $id
++  "id": "123"
+ }
+`)
+
+	// Templates are disabled by default.
+	c.VarTemplates = false
+	assert.NoError(t, c.FailNotEqual([]byte(`{"cmd":"echo ${id}"}`), []byte(`{"cmd":"echo ${id}"}`)))
+}
+
+func TestComparer_FailNotEqual_sideBySideColoring(t *testing.T) {
+	c := assertjson.Comparer{DiffFormat: assertjson.DiffSideBySide, ColumnWidth: 10}
+	c.FormatterConfig.Coloring = true
+
+	assert.EqualError(t, c.FailNotEqual([]byte(`{"a":1}`), []byte(`{"a":2}`)), "not equal:\n"+
+		"{            {\n"+
+		"\x1b[30;41m  \"a\": 1  \x1b[0m | \x1b[30;42m  \"a\": 2  \x1b[0m\n"+
+		"}            }\n")
+}
+
+func TestComparer_FailNotEqual_invalidSelector(t *testing.T) {
+	c := assertjson.Comparer{IgnorePaths: []string{"/a", "$.items[*"}}
+
+	assert.EqualError(t, c.FailNotEqual([]byte(`{"a":1}`), []byte(`{"a":1}`)),
+		`IgnorePaths: invalid selector "$.items[*": missing ]`)
+
+	c = assertjson.Comparer{}
+	c.DifferConfig.UnorderedArrayPaths = []string{"/a~2"}
+
+	assert.EqualError(t, c.FailNotEqualReader(strings.NewReader(`{"a":[1]}`), strings.NewReader(`{"a":[1]}`)),
+		`UnorderedArrayPaths: invalid selector "/a~2": invalid escape sequence`)
+
+	c = assertjson.Comparer{}
+	c.DifferConfig.ArrayKeys = map[string]string{"/users": "id", "$.orders[*": "id"}
+
+	assert.EqualError(t, c.FailNotEqual([]byte(`{"users":[]}`), []byte(`{"users":[]}`)),
+		`ArrayKeys: invalid selector "$.orders[*": missing ]`)
+}
+
+func TestComparer_FailNotEqual_varsAnnotationsMoved(t *testing.T) {
+	v := &shared.Vars{}
+	v.Set("$v", 1)
+	v.Set("$w", 3)
+	v.Set("$id", 5)
+
+	c := assertjson.Comparer{Vars: v, VarTemplates: true}
+	c.DifferConfig.ArrayKeys = map[string]string{"/items": "id"}
+
+	// Moved item is annotated with variables of its expected value.
+	err := c.FailNotEqual(
+		[]byte(`{"items":[{"id":1,"v":"$v"},{"id":2,"v":2},{"id":3,"v":"$w"}],"ref":"${id}/${id}"}`),
+		[]byte(`{"items":[{"id":2,"v":2},{"id":3,"v":3},{"id":1,"v":4}],"ref":"5/6"}`),
+	)
+	assert.EqualError(t, err, `not equal:
+ {
+   "items": [
+-    {
+-      "id": 1,
+-      "v": 1 // $v
+-    },
+     {
+       "id": 2,
+       "v": 2
+     },
+     {
+       "id": 3,
+       "v": 3
+     },
+     {
+       "id": 1,
+-      "v": 1 // $v
++      "v": 4
+     }
+   ],
+-  "ref": "5/5" // $id
++  "ref": "5/6"
+ }
+`)
+}
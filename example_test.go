@@ -17,8 +17,8 @@ func Example() {
 	)
 
 	// Output:
-	// Error Trace:	equal.go:82
-	// 	            				equal.go:57
+	// Error Trace:	equal.go:133
+	// 	            				equal.go:108
 	// 	            				example_test.go:14
 	// 	Error:      	Not equal:
 	// 	            	 {
@@ -0,0 +1,56 @@
+package assertjson
+
+import (
+	"fmt"
+
+	"github.com/swaggest/assertjson/diff"
+)
+
+// DiffFormat defines presentation of difference in failure message.
+type DiffFormat int
+
+// Diff formats.
+const (
+	// DiffASCII shows expected and actual lines one after another with "-" and "+" markers.
+	DiffASCII DiffFormat = iota
+
+	// DiffSideBySide shows expected lines in the left column and actual lines in the right column.
+	DiffSideBySide
+
+	// DiffPathSummary shows one line per change with JSON Pointer, e.g. `/users/3/email: "a@x" -> "b@x"`.
+	DiffPathSummary
+)
+
+// formatDiff renders difference in configured format, ASCII lines of expected values are annotated with
+// names of variables by JSON Pointer.
+func (c Comparer) formatDiff(expDecoded interface{}, d diff.Diff, annotations map[string]string) (string, error) {
+	if c.DiffFormat == DiffPathSummary {
+		return diff.NewPathSummaryFormatter(diff.PathSummaryFormatterConfig{}).Format(d)
+	}
+
+	asciiConfig := c.FormatterConfig
+	if asciiConfig.Annotations == nil {
+		asciiConfig.Annotations = annotations
+	}
+
+	if c.DiffFormat == DiffSideBySide {
+		// Same as in diff.SideBySideFormatter, lines are parsed by markers and columns are colored instead.
+		asciiConfig.Coloring = false
+	}
+
+	diffText, err := diff.NewASCIIFormatter(expDecoded, asciiConfig).Format(d)
+	if err != nil {
+		return "", fmt.Errorf("failed to format diff:\n%wv", err)
+	}
+
+	diffText = c.reduceDiff(diffText)
+
+	if c.DiffFormat == DiffSideBySide {
+		sideBySideConfig := diff.SideBySideFormatterConfig{ASCIIFormatterConfig: asciiConfig, ColumnWidth: c.ColumnWidth}
+		sideBySideConfig.Coloring = c.FormatterConfig.Coloring
+
+		diffText = diff.SideBySide(diffText, sideBySideConfig)
+	}
+
+	return diffText, nil
+}
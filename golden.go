@@ -0,0 +1,232 @@
+package assertjson
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/iancoleman/orderedmap"
+	"github.com/stretchr/testify/assert"
+)
+
+// UpdateGoldenEnv is a name of environment variable to enable rewriting of golden files, e.g. ASSERTJSON_UPDATE_GOLDEN=1.
+const UpdateGoldenEnv = "ASSERTJSON_UPDATE_GOLDEN"
+
+// EqualFile compares JSON document from golden file with actual payload ignoring string values "<ignore-diff>".
+func EqualFile(t TestingT, path string, actual []byte, msgAndArgs ...interface{}) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	return defaultComparer.EqualFile(t, path, actual, msgAndArgs...)
+}
+
+// EqualMarshalFile marshals actual value and compares it with JSON document from golden file
+// ignoring string values "<ignore-diff>".
+func EqualMarshalFile(t TestingT, path string, actualValue interface{}, msgAndArgs ...interface{}) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	return defaultComparer.EqualMarshalFile(t, path, actualValue, msgAndArgs...)
+}
+
+// EqualFile compares JSON document from golden file with actual payload.
+//
+// If UpdateGolden is enabled or ASSERTJSON_UPDATE_GOLDEN environment variable is set, golden file is
+// rewritten with actual payload before comparison. Expected values that are ignore markers, variables or
+// templates with variables, and placeholders or regexps that accept actual values are kept at their locations.
+func (c Comparer) EqualFile(t TestingT, path string, actual []byte, msgAndArgs ...interface{}) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	if c.updateGolden() {
+		if err := c.writeGolden(path, actual); err != nil {
+			assert.Fail(t, fmt.Sprintf("Failed to update golden file: %v", err), msgAndArgs...)
+
+			return false
+		}
+	}
+
+	expected, err := ioutil.ReadFile(path) //nolint:gosec // Golden file is provided by test.
+	if err != nil {
+		assert.Fail(t, fmt.Sprintf("Failed to read golden file, set %s=1 to create it: %v", UpdateGoldenEnv, err),
+			msgAndArgs...)
+
+		return false
+	}
+
+	return c.Equal(t, expected, actual, msgAndArgs...)
+}
+
+// EqualMarshalFile marshals actual value and compares it with JSON document from golden file.
+func (c Comparer) EqualMarshalFile(t TestingT, path string, actualValue interface{}, msgAndArgs ...interface{}) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	actual, err := MarshalIndentCompact(actualValue, "", "  ", 80)
+	assert.NoError(t, err, "failed to marshal actual value")
+
+	if len(msgAndArgs) == 0 {
+		msgAndArgs = append(msgAndArgs, string(actual))
+	}
+
+	return c.EqualFile(t, path, actual, msgAndArgs...)
+}
+
+func (c Comparer) updateGolden() bool {
+	if c.UpdateGolden {
+		return true
+	}
+
+	update, err := strconv.ParseBool(os.Getenv(UpdateGoldenEnv))
+
+	return err == nil && update
+}
+
+// writeGolden rewrites golden file with actual payload keeping matching patterns of existing file.
+func (c Comparer) writeGolden(path string, actual []byte) error {
+	c.Vars = c.varStore()
+
+	var actDecoded, expDecoded interface{}
+
+	if err := unmarshal(actual, &actDecoded); err != nil {
+		return fmt.Errorf("failed to unmarshal actual: %w", err)
+	}
+
+	ordered, err := unmarshalOrdered(actual)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal actual: %w", err)
+	}
+
+	existing, err := ioutil.ReadFile(path) //nolint:gosec // Golden file is provided by test.
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	// Existing document that is not a valid JSON is replaced entirely.
+	if err == nil && unmarshal(existing, &expDecoded) == nil {
+		cmp, err := c.newComparison(false)
+		if err != nil {
+			return err
+		}
+
+		cmp.dryRun = true
+		ordered = cmp.keepPatterns(expDecoded, actDecoded, ordered)
+	}
+
+	updated, err := MarshalIndentCompact(ordered, "", "  ", 80)
+	if err != nil {
+		return err
+	}
+
+	updated = append(updated, '\n')
+
+	if bytes.Equal(existing, updated) {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, updated, 0o600)
+}
+
+// keepPatterns replaces values of ordered actual document with expected values that accept them.
+func (c *comparison) keepPatterns(expected, actual, ordered interface{}) interface{} {
+	switch e := expected.(type) {
+	case string:
+		if c.patternAccepted(e, actual) {
+			return e
+		}
+	case map[string]interface{}:
+		a, ok := actual.(map[string]interface{})
+		o, oOk := ordered.(orderedmap.OrderedMap)
+
+		if !ok || !oOk {
+			return ordered
+		}
+
+		for k, ev := range e {
+			if av, found := a[k]; found {
+				ov, _ := o.Get(k)
+				o.Set(k, c.keepPatterns(ev, av, ov))
+			}
+		}
+	case []interface{}:
+		a, ok := actual.([]interface{})
+		o, oOk := ordered.([]interface{})
+
+		if !ok || !oOk {
+			return ordered
+		}
+
+		for i, j := range c.goldenPairs(e, a) {
+			if j != -1 {
+				o[j] = c.keepPatterns(e[i], a[j], o[j])
+			}
+		}
+	}
+
+	return ordered
+}
+
+// goldenPairs returns indexes of actual items that correspond to expected items, -1 if there is none.
+//
+// Matching items are paired in the same order, items between them are paired by position only if there are
+// as many expected items as actual, so that patterns are not kept for inserted, removed or reordered items.
+func (c *comparison) goldenPairs(exp, act []interface{}) []int {
+	pairs := c.matchSubsequence(exp, act)
+	prevE, prevA := -1, -1
+
+	for i := 0; i <= len(exp); i++ {
+		j := len(act)
+
+		if i < len(exp) {
+			if pairs[i] == -1 {
+				continue
+			}
+
+			j = pairs[i]
+		}
+
+		if i-prevE == j-prevA {
+			for k := 1; k < i-prevE; k++ {
+				pairs[prevE+k] = prevA + k
+			}
+		}
+
+		prevE, prevA = i, j
+	}
+
+	return pairs
+}
+
+// patternAccepted checks if expected string should be kept in golden file for actual value.
+func (c Comparer) patternAccepted(s string, actual interface{}) bool {
+	if c.IgnoreDiff != "" && s == c.IgnoreDiff {
+		return true
+	}
+
+	if c.isVar(s) || c.isTemplate(s) {
+		return true
+	}
+
+	if c.Placeholders != nil {
+		if check, found := c.Placeholders.Get(s); found {
+			return check(actual) == nil
+		}
+	}
+
+	if pattern, ok := c.regexpPattern(s); ok {
+		return matchRegexp(pattern, actual) == nil
+	}
+
+	return false
+}
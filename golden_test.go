@@ -0,0 +1,123 @@
+package assertjson_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/bool64/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+)
+
+func TestComparer_EqualFile(t *testing.T) {
+	t.Setenv(assertjson.UpdateGoldenEnv, "")
+
+	path := filepath.Join(t.TempDir(), "testdata", "golden.json")
+
+	v := &shared.Vars{}
+	c := assertjson.Comparer{
+		IgnoreDiff:   assertjson.IgnoreDiff,
+		Placeholders: assertjson.DefaultPlaceholders,
+		Vars:         v,
+		UpdateGolden: true,
+	}
+
+	// Golden file is created with actual payload.
+	assert.True(t, c.EqualFile(t, path, []byte(`{"id":1,"name":"Bob","createdAt":"2021-01-01T00:00:00Z"}`)))
+
+	golden, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":1,"name":"Bob","createdAt":"2021-01-01T00:00:00Z"}`+"\n", string(golden))
+
+	require.NoError(t, ioutil.WriteFile(path, []byte(`{
+  "id":"$id","name":"Bob","createdAt":"<ignore-diff>","tags":["<any-string>","<any-string>"],"extra":1
+}`), 0o600))
+
+	// Patterns are kept, new values are written.
+	assert.True(t, c.EqualFile(t, path,
+		[]byte(`{"id":2,"name":"Alice","createdAt":"2021-01-02T00:00:00Z","tags":["a",3],"new":true}`)))
+
+	golden, err = ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `{
+  "id":"$id","name":"Alice","createdAt":"<ignore-diff>",
+  "tags":["<any-string>",3],"new":true
+}
+`, string(golden))
+
+	id, found := v.Get("$id")
+	assert.True(t, found)
+	assert.Equal(t, int64(2), id)
+
+	// Without update golden file is only compared.
+	c.UpdateGolden = false
+
+	assert.True(t, c.EqualMarshalFile(t, path, map[string]interface{}{
+		"id": 2, "name": "Alice", "createdAt": "now", "tags": []interface{}{"b", 3}, "new": true,
+	}))
+
+	assert.False(t, c.EqualFile(testingT(func(format string, args ...interface{}) {}), path,
+		[]byte(`{"id":2,"name":"Bob","createdAt":"now","tags":["b",3],"new":true}`)))
+}
+
+func TestComparer_EqualFile_insertedItems(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "golden.json")
+	c := assertjson.Comparer{IgnoreDiff: assertjson.IgnoreDiff, UpdateGolden: true}
+
+	require.NoError(t, ioutil.WriteFile(path, []byte(`[
+  {"id":"<ignore-diff>","name":"a"},{"id":"<ignore-diff>","name":"b"},
+  {"id":"<ignore-diff>","name":"c"}
+]`), 0o600))
+
+	// Patterns follow their items, inserted items are written as is.
+	assert.True(t, c.EqualFile(t, path,
+		[]byte(`[{"id":4,"name":"new"},{"id":1,"name":"a"},{"id":2,"name":"b2"},{"id":3,"name":"c"}]`)))
+
+	golden, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `[
+  {"id":4,"name":"new"},{"id":"<ignore-diff>","name":"a"},
+  {"id":"<ignore-diff>","name":"b2"},{"id":"<ignore-diff>","name":"c"}
+]
+`, string(golden))
+
+	// Patterns of removed items are not kept for items that take their positions.
+	assert.True(t, c.EqualFile(t, path, []byte(`[{"id":1,"name":"a"},{"id":3,"name":"c"},{"id":5,"name":"e"}]`)))
+
+	golden, err = ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `[
+  {"id":"<ignore-diff>","name":"a"},{"id":"<ignore-diff>","name":"c"},
+  {"id":5,"name":"e"}
+]
+`, string(golden))
+}
+
+func TestEqualFile_missing(t *testing.T) {
+	t.Setenv(assertjson.UpdateGoldenEnv, "")
+
+	var msg string
+
+	assert.False(t, assertjson.EqualFile(testingT(func(format string, args ...interface{}) {
+		msg = args[0].(string)
+	}), filepath.Join(t.TempDir(), "missing.json"), []byte(`{}`)))
+
+	assert.Contains(t, msg, "Failed to read golden file, set ASSERTJSON_UPDATE_GOLDEN=1 to create it:")
+}
+
+func TestEqualMarshalFile_update(t *testing.T) {
+	t.Setenv(assertjson.UpdateGoldenEnv, "1")
+
+	path := filepath.Join(t.TempDir(), "golden.json")
+
+	assert.True(t, assertjson.EqualMarshalFile(t, path, struct {
+		B string `json:"b"`
+		A []int  `json:"a"`
+	}{B: "foo", A: []int{1, 2, 3}}))
+
+	golden, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `{"b":"foo","a":[1,2,3]}`+"\n", string(golden))
+}
@@ -0,0 +1,278 @@
+package assertjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ExpectedResponse describes expected HTTP response.
+type ExpectedResponse struct {
+	// Status is an expected status code, not checked if zero.
+	Status int
+
+	// Headers are expected values of response headers, e.g. {"Cache-Control": "no-cache"}.
+	// Empty value expects header to be absent, header that is present with empty value fails the check.
+	Headers map[string]string
+
+	// ContentType is an expected media type of Content-Type header, e.g. "application/json".
+	// Parameters, e.g. "charset=utf-8", are only checked if present in expected value. Not checked if empty.
+	ContentType string
+
+	// Body is an expected JSON body, not checked if nil.
+	Body []byte
+}
+
+// ResponseError is returned when HTTP response does not meet expectations.
+type ResponseError struct {
+	// Method and URL describe request of the response, empty if request is unknown.
+	Method string
+	URL    string
+
+	// Failures explain unmet expectations.
+	Failures []string
+
+	// Result is a difference of JSON body, nil if body is equal or was not compared.
+	Result *Result
+
+	// Body is an actual response body.
+	Body []byte
+}
+
+// Error returns explanation of failures with compacted actual body.
+func (e *ResponseError) Error() string {
+	msg := "unexpected response"
+
+	if r := strings.TrimSpace(e.Method + " " + e.URL); r != "" {
+		msg += " of " + r
+	}
+
+	msg += ":\n" + strings.Join(e.Failures, "\n") + "\n"
+
+	if len(e.Body) > 0 {
+		msg += "actual body:\n" + compactBody(e.Body) + "\n"
+	}
+
+	return msg
+}
+
+// EqualResponse checks HTTP response status, headers and JSON body ignoring string values "<ignore-diff>".
+func EqualResponse(t TestingT, expected ExpectedResponse, resp *http.Response, msgAndArgs ...interface{}) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	return defaultComparer.EqualResponse(t, expected, resp, msgAndArgs...)
+}
+
+// MatchesResponse checks HTTP response status, headers and JSON body ignoring added fields
+// and string values "<ignore-diff>".
+func MatchesResponse(t TestingT, expected ExpectedResponse, resp *http.Response, msgAndArgs ...interface{}) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	return defaultComparer.MatchesResponse(t, expected, resp, msgAndArgs...)
+}
+
+// FailNotEqualResponse returns error if HTTP response does not meet expectations, nil otherwise.
+func FailNotEqualResponse(expected ExpectedResponse, resp *http.Response) error {
+	return defaultComparer.FailNotEqualResponse(expected, resp)
+}
+
+// FailMismatchResponse returns error if HTTP response does not meet expectations, nil otherwise.
+// It ignores added fields in actual body.
+func FailMismatchResponse(expected ExpectedResponse, resp *http.Response) error {
+	return defaultComparer.FailMismatchResponse(expected, resp)
+}
+
+// RecordedResponse returns response of a recorder with request, request is used in failure message.
+func RecordedResponse(req *http.Request, rec *httptest.ResponseRecorder) *http.Response {
+	resp := rec.Result()
+	resp.Request = req
+
+	return resp
+}
+
+// EqualResponse checks HTTP response status, headers and JSON body.
+func (c Comparer) EqualResponse(t TestingT, expected ExpectedResponse, resp *http.Response, msgAndArgs ...interface{}) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	return c.assertResponse(t, c.FailNotEqualResponse(expected, resp), msgAndArgs...)
+}
+
+// MatchesResponse checks HTTP response status, headers and JSON body ignoring added fields in actual body.
+func (c Comparer) MatchesResponse(t TestingT, expected ExpectedResponse, resp *http.Response, msgAndArgs ...interface{}) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	return c.assertResponse(t, c.FailMismatchResponse(expected, resp), msgAndArgs...)
+}
+
+// FailNotEqualResponse returns error if HTTP response does not meet expectations, nil otherwise.
+func (c Comparer) FailNotEqualResponse(expected ExpectedResponse, resp *http.Response) error {
+	return c.failResponse(expected, resp, false)
+}
+
+// FailMismatchResponse returns error if HTTP response does not meet expectations, nil otherwise.
+// It ignores added fields in actual body.
+func (c Comparer) FailMismatchResponse(expected ExpectedResponse, resp *http.Response) error {
+	return c.failResponse(expected, resp, true)
+}
+
+func (c Comparer) assertResponse(t TestingT, err error, msgAndArgs ...interface{}) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	if err == nil {
+		return true
+	}
+
+	msg := err.Error()
+	msg = strings.ToUpper(msg[0:1]) + msg[1:]
+	assert.Fail(t, msg, msgAndArgs...)
+
+	return false
+}
+
+func (c Comparer) failResponse(expected ExpectedResponse, resp *http.Response, ignoreAdded bool) error {
+	e := &ResponseError{}
+
+	if resp == nil {
+		e.Failures = append(e.Failures, "missing response")
+
+		return e
+	}
+
+	if resp.Request != nil {
+		e.Method = resp.Request.Method
+
+		if resp.Request.URL != nil {
+			e.URL = resp.Request.URL.String()
+		}
+	}
+
+	if expected.Status != 0 && expected.Status != resp.StatusCode {
+		e.Failures = append(e.Failures, fmt.Sprintf("status: expected %d, got %d", expected.Status, resp.StatusCode))
+	}
+
+	keys := make([]string, 0, len(expected.Headers))
+	for k := range expected.Headers {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if f := headerFailure(k, expected.Headers[k], resp.Header.Values(k)); f != "" {
+			e.Failures = append(e.Failures, f)
+		}
+	}
+
+	if ct := resp.Header.Get("Content-Type"); expected.ContentType != "" && !contentTypeMatches(expected.ContentType, ct) {
+		e.Failures = append(e.Failures, fmt.Sprintf("content type: expected %q, got %q", expected.ContentType, ct))
+	}
+
+	body, err := readBody(resp)
+	if err != nil {
+		e.Failures = append(e.Failures, "failed to read body: "+err.Error())
+	}
+
+	e.Body = body
+
+	if expected.Body != nil && err == nil {
+		res, err := c.compareBytes(expected.Body, body, ignoreAdded)
+
+		switch {
+		case err != nil:
+			e.Failures = append(e.Failures, "body: "+err.Error())
+		case !res.Equal():
+			e.Result = res
+			e.Failures = append(e.Failures, "body not equal:\n"+strings.TrimSuffix(res.Diff, "\n"))
+		}
+	}
+
+	if len(e.Failures) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+// headerFailure explains mismatch of actual header values, empty expected value requires header to be absent.
+func headerFailure(name, expected string, values []string) string {
+	switch {
+	case expected == "" && len(values) > 0:
+		return fmt.Sprintf("header %s: expected absent, got %q", name, values[0])
+	case expected == "":
+		return ""
+	case len(values) == 0:
+		return fmt.Sprintf("header %s: expected %q, got absent", name, expected)
+	case values[0] != expected:
+		return fmt.Sprintf("header %s: expected %q, got %q", name, expected, values[0])
+	}
+
+	return ""
+}
+
+// readBody reads response body and replaces it with a copy to allow further reading.
+func readBody(resp *http.Response) ([]byte, error) {
+	if resp.Body == nil {
+		return nil, nil
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := resp.Body.Close(); err != nil {
+		return nil, err
+	}
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	return body, nil
+}
+
+// contentTypeMatches checks if actual media type is equal to expected and has expected parameters.
+func contentTypeMatches(expected, actual string) bool {
+	expType, expParams, err := mime.ParseMediaType(expected)
+	if err != nil {
+		return expected == actual
+	}
+
+	actType, actParams, err := mime.ParseMediaType(actual)
+	if err != nil || expType != actType {
+		return false
+	}
+
+	for k, v := range expParams {
+		if !strings.EqualFold(actParams[k], v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// compactBody renders JSON body with compact indentation, invalid JSON is returned as is.
+func compactBody(body []byte) string {
+	if b, err := MarshalIndentCompact(json.RawMessage(body), "", "  ", 80); err == nil {
+		return string(b)
+	}
+
+	return string(body)
+}
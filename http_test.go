@@ -0,0 +1,107 @@
+package assertjson_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+)
+
+func userHandler(rw http.ResponseWriter, _ *http.Request) {
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	rw.Header().Set("Cache-Control", "no-cache")
+	rw.Header().Set("X-Trace", "")
+	rw.WriteHeader(http.StatusOK)
+
+	_, _ = rw.Write([]byte(`{"id":1,"name":"Bob","createdAt":"2021-01-01T00:00:00Z"}`))
+}
+
+func TestEqualResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(userHandler))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/users/1") //nolint:noctx
+	require.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	assert.True(t, assertjson.EqualResponse(t, assertjson.ExpectedResponse{
+		Status:      http.StatusOK,
+		Headers:     map[string]string{"Cache-Control": "no-cache"},
+		ContentType: "application/json",
+		Body:        []byte(`{"id":1,"name":"Bob","createdAt":"<ignore-diff>"}`),
+	}, resp))
+
+	// Body can be read after assertion.
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":1,"name":"Bob","createdAt":"2021-01-01T00:00:00Z"}`, string(body))
+}
+
+func TestComparer_FailNotEqualResponse(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	rec := httptest.NewRecorder()
+
+	userHandler(rec, req)
+
+	err := assertjson.FailNotEqualResponse(assertjson.ExpectedResponse{
+		Status:      http.StatusCreated,
+		Headers:     map[string]string{"Cache-Control": "no-store", "X-Request-Id": "", "X-Trace": "", "Etag": "abc"},
+		ContentType: "application/json; charset=ascii",
+		Body:        []byte(`{"id":1,"name":"Alice"}`),
+	}, assertjson.RecordedResponse(req, rec))
+
+	var re *assertjson.ResponseError
+
+	require.True(t, errors.As(err, &re))
+	require.NotNil(t, re.Result)
+	assert.Len(t, re.Result.Changes, 2)
+	assert.Equal(t, `unexpected response of GET /users/1:
+status: expected 201, got 200
+header Cache-Control: expected "no-store", got "no-cache"
+header Etag: expected "abc", got absent
+header X-Trace: expected absent, got ""
+content type: expected "application/json; charset=ascii", got "application/json; charset=utf-8"
+body not equal:
+ {
+   "id": 1,
+-  "name": "Alice"
++  "name": "Bob"
++  "createdAt": "2021-01-01T00:00:00Z"
+ }
+actual body:
+{"id":1,"name":"Bob","createdAt":"2021-01-01T00:00:00Z"}
+`, err.Error())
+}
+
+func TestComparer_MatchesResponse(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	userHandler(rec, nil)
+
+	c := assertjson.Comparer{}
+
+	assert.True(t, c.MatchesResponse(t, assertjson.ExpectedResponse{
+		Status: http.StatusOK,
+		Body:   []byte(`{"name":"Bob"}`),
+	}, rec.Result()))
+
+	assert.False(t, c.MatchesResponse(testingT(func(format string, args ...interface{}) {
+		assert.Contains(t, args[0], "Unexpected response:")
+		assert.Contains(t, args[0], "status: expected 404, got 200")
+	}), assertjson.ExpectedResponse{Status: http.StatusNotFound}, rec.Result()))
+}
+
+func TestComparer_FailNotEqualResponse_nil(t *testing.T) {
+	err := assertjson.FailNotEqualResponse(assertjson.ExpectedResponse{Status: http.StatusOK}, nil)
+
+	var re *assertjson.ResponseError
+
+	require.True(t, errors.As(err, &re))
+	assert.Equal(t, "unexpected response:\nmissing response\n", err.Error())
+}
@@ -4,6 +4,24 @@ import (
 	"strings"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/swaggest/assertjson/diff"
+)
+
+// ArrayMatching defines how expected arrays are matched with actual arrays in Matches.
+type ArrayMatching int
+
+// Array matching modes.
+const (
+	// ArrayMatchExact requires every item of actual array to match expected item at the same position.
+	ArrayMatchExact ArrayMatching = iota
+
+	// ArrayMatchSubsequence requires expected items to match actual items in the same order,
+	// extra actual items are ignored.
+	ArrayMatchSubsequence
+
+	// ArrayMatchSubset requires expected items to match actual items in any order,
+	// extra actual items are ignored.
+	ArrayMatchSubset
 )
 
 // Matches compares two JSON payloads.
@@ -90,3 +108,161 @@ func MatchesMarshal(t TestingT, expected []byte, actualValue interface{}, msgAnd
 
 	return defaultComparer.MatchesMarshal(t, expected, actualValue, msgAndArgs...)
 }
+
+// alignArrays replaces actual arrays with matched expected items, so that only unmatched expected items
+// are reported as deleted, positions of deleted items refer to expected array. Actual values of matched items
+// and unmatched actual items are not present in aligned document.
+//
+// Variables of matched items are collected in order of expected items, an item that conflicts with variables
+// collected from previous items is unmatched.
+func (c *comparison) alignArrays(exp, act interface{}, path []string) interface{} {
+	switch e := exp.(type) {
+	case map[string]interface{}:
+		a, ok := act.(map[string]interface{})
+		if !ok {
+			return act
+		}
+
+		res := make(map[string]interface{}, len(a))
+
+		for k, v := range a {
+			if ev, ok := e[k]; ok {
+				v = c.alignArrays(ev, v, append(path[:len(path):len(path)], k))
+			}
+
+			res[k] = v
+		}
+
+		return res
+
+	case []interface{}:
+		a, ok := act.([]interface{})
+		if !ok {
+			return act
+		}
+
+		var pairs []int
+		if c.ArrayMatching == ArrayMatchSubset {
+			pairs = c.matchSubset(e, a)
+		} else {
+			pairs = c.matchSubsequence(e, a)
+		}
+
+		res := make([]interface{}, 0, len(e))
+
+		for i, j := range pairs {
+			if j == -1 {
+				continue
+			}
+
+			itemPath := append(path[:len(path):len(path)], diff.Index(i).String())
+
+			// Collecting variables of matched item.
+			aligned := c.alignArrays(e[i], a[j], itemPath)
+			if len(c.filterDeltas(c.compare(e[i], aligned).Deltas(), itemPath)) > 0 {
+				continue
+			}
+
+			res = append(res, e[i])
+		}
+
+		return res
+	}
+
+	return act
+}
+
+// matchSubsequence returns indexes of actual items that match expected items in the same order, -1 if unmatched.
+func (c *comparison) matchSubsequence(exp, act []interface{}) []int {
+	pairs := make([]int, len(exp))
+	next := 0
+
+	for i, e := range exp {
+		pairs[i] = -1
+
+		for j := next; j < len(act); j++ {
+			if c.itemMatches(e, act[j]) {
+				pairs[i] = j
+				next = j + 1
+
+				break
+			}
+		}
+	}
+
+	return pairs
+}
+
+// matchSubset returns indexes of actual items that match expected items in any order, -1 if unmatched.
+//
+// Maximum matching is found with augmenting paths, so that an expected item is not left unmatched
+// because its only match is taken by another item with alternatives.
+func (c *comparison) matchSubset(exp, act []interface{}) []int {
+	matches := make([][]bool, len(exp))
+	for i, e := range exp {
+		matches[i] = make([]bool, len(act))
+		for j, a := range act {
+			matches[i][j] = c.itemMatches(e, a)
+		}
+	}
+
+	pairs := make([]int, len(exp))
+	owners := make([]int, len(act))
+
+	for j := range owners {
+		owners[j] = -1
+	}
+
+	var augment func(i int, visited []bool) bool
+
+	augment = func(i int, visited []bool) bool {
+		for j := range act {
+			if !matches[i][j] || visited[j] {
+				continue
+			}
+
+			visited[j] = true
+
+			if owners[j] == -1 || augment(owners[j], visited) {
+				owners[j] = i
+
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for i := range exp {
+		augment(i, make([]bool, len(act)))
+	}
+
+	for i := range pairs {
+		pairs[i] = -1
+	}
+
+	for j, i := range owners {
+		if i != -1 {
+			pairs[i] = j
+		}
+	}
+
+	return pairs
+}
+
+// itemMatches checks if actual item matches expected item without collecting variables.
+func (c *comparison) itemMatches(exp, act interface{}) bool {
+	trial := comparison{
+		Comparer:    c.Comparer,
+		ignoreAdded: c.ignoreAdded,
+		dryRun:      true,
+		ignorePaths: c.ignorePaths,
+		differ:      c.differ,
+	}
+
+	if trial.ignoreAdded && c.ArrayMatching != ArrayMatchExact {
+		act = trial.alignArrays(exp, act, nil)
+	}
+
+	return len(trial.filterDeltas(trial.compare(exp, act).Deltas(), nil)) == 0
+}
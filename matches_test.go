@@ -2,10 +2,14 @@ package assertjson_test
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 
+	"github.com/bool64/shared"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/swaggest/assertjson"
+	"github.com/swaggest/assertjson/diff"
 )
 
 func TestFailMismatch(t *testing.T) {
@@ -40,3 +44,88 @@ func TestFailMismatch(t *testing.T) {
 	assertjson.MatchesMarshal(t, exp, act)
 	assertjson.Matches(t, exp, act)
 }
+
+func TestComparer_FailMismatch_arrayMatching(t *testing.T) {
+	c := assertjson.Comparer{IgnoreDiff: assertjson.IgnoreDiff, ArrayMatching: assertjson.ArrayMatchSubsequence}
+
+	exp := []byte(`{"items": [{"id": 2}, {"id": 4, "tags": ["b"]}]}`)
+	act := []byte(`{"items": [{"id": 1}, {"id": 2, "v": 1}, {"id": 3}, {"id": 4, "tags": ["a", "b"]}], "total": 4}`)
+
+	assert.NoError(t, c.FailMismatch(exp, act))
+	assert.Error(t, c.FailNotEqual(exp, act))
+	assert.Error(t, c.FailMismatch([]byte(`[3, 1]`), []byte(`[1, 2, 3]`)))
+
+	assert.EqualError(t, c.FailMismatch([]byte(`{"items": [{"id": 2}, {"id": 5}, {"id": 4}]}`), act), `not equal:
+ {
+   "items": [
+     {
+       "id": 2
+     },
+-    {
+-      "id": 5
+-    },
+     {
+       "id": 4
+     }
+   ]
+ }
+`)
+
+	c.ArrayMatching = assertjson.ArrayMatchSubset
+
+	assert.NoError(t, c.FailMismatch([]byte(`[3, 1]`), []byte(`[1, 2, 3]`)))
+	assert.NoError(t, c.FailMismatch([]byte(`[{"a": 1}, {"a": 1, "b": 2}]`), []byte(`[{"a": 1, "b": 2}, {"a": 1}]`)))
+	assert.NoError(t, c.FailMismatch([]byte(`[{"id": "<ignore-diff>", "n": "x"}]`), []byte(`[{"id": 1, "n": "y"}, {"id": 2, "n": "x"}]`)))
+	assert.EqualError(t, c.FailMismatch([]byte(`[3, 4, 1]`), []byte(`[1, 2, 3]`)), `not equal:
+ [
+   3,
+-  4,
+   1
+ ]
+`)
+}
+
+func TestComparer_FailMismatch_arrayMatchingResult(t *testing.T) {
+	c := assertjson.Comparer{IgnoreDiff: assertjson.IgnoreDiff, ArrayMatching: assertjson.ArrayMatchSubset}
+
+	err := c.FailMismatch(
+		[]byte(`{"a": 1, "items": [{"id": "<ignore-diff>", "n": "c"}, {"n": "x"}, {"n": "a"}]}`),
+		[]byte(`{"a": 2, "items": [{"id": 7, "n": "a"}, {"n": "b"}, {"id": 8, "n": "c"}]}`),
+	)
+
+	var ne *assertjson.NotEqualError
+
+	require.True(t, errors.As(err, &ne))
+
+	// Changes refer to expected positions of unmatched items, matched and extra actual items are not reported.
+	assert.Equal(t, []diff.Change{
+		{Type: diff.ChangeModified, Path: "/a", OldValue: int64(1), NewValue: int64(2)},
+		{Type: diff.ChangeDeleted, Path: "/items/1", OldValue: map[string]interface{}{"n": "x"}},
+	}, ne.Result.Changes)
+}
+
+func TestComparer_FailMismatch_arrayMatchingVars(t *testing.T) {
+	v := &shared.Vars{}
+	c := assertjson.Comparer{Vars: v, ArrayMatching: assertjson.ArrayMatchSubset}
+
+	assert.NoError(t, c.FailMismatch(
+		[]byte(`{"users": [{"name": "bob", "id": "$bobId"}]}`),
+		[]byte(`{"users": [{"name": "alice", "id": 1}, {"name": "bob", "id": 2}]}`),
+	))
+
+	id, found := v.Get("$bobId")
+	assert.True(t, found)
+	assert.Equal(t, int64(2), id)
+
+	// Items that conflict with variables of previous items are not matched.
+	for _, m := range []assertjson.ArrayMatching{assertjson.ArrayMatchSubset, assertjson.ArrayMatchSubsequence} {
+		c = assertjson.Comparer{Vars: &shared.Vars{}, ArrayMatching: m}
+
+		err := c.FailMismatch(
+			[]byte(`[{"id":"$id","n":"a"},{"id":"$id","n":"b"}]`),
+			[]byte(`[{"id":1,"n":"a"},{"id":2,"n":"b"}]`),
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "variable $id bound to 1 but found 2 at /1/id", m)
+	}
+}
@@ -0,0 +1,157 @@
+package assertjson
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// Built-in placeholders of expected values.
+const (
+	AnyString      = "<any-string>"
+	AnyNumber      = "<any-number>"
+	AnyBool        = "<any-bool>"
+	AnyObject      = "<any-object>"
+	AnyArray       = "<any-array>"
+	NonEmptyString = "<non-empty-string>"
+	UUID           = "<uuid>"
+	RFC3339        = "<rfc3339>"
+	Email          = "<email>"
+	URI            = "<uri>"
+	Base64         = "<base64>"
+)
+
+// PlaceholderFunc checks actual value that corresponds to a placeholder in expected document.
+//
+// Actual value is decoded with json.Number for numbers. Returned error describes the mismatch,
+// nil means actual value is accepted.
+type PlaceholderFunc func(actual interface{}) error
+
+// Placeholders is a registry of named placeholders.
+type Placeholders struct {
+	mu    sync.RWMutex
+	items map[string]PlaceholderFunc
+}
+
+// DefaultPlaceholders is a registry used by package-level assertions.
+var DefaultPlaceholders = NewPlaceholders()
+
+// NewPlaceholders creates a registry with built-in placeholders.
+func NewPlaceholders() *Placeholders {
+	p := &Placeholders{}
+
+	p.Register(AnyString, typeCheck("string"))
+	p.Register(AnyNumber, typeCheck("number"))
+	p.Register(AnyBool, typeCheck("boolean"))
+	p.Register(AnyObject, typeCheck("object"))
+	p.Register(AnyArray, typeCheck("array"))
+
+	p.Register(NonEmptyString, formatCheck("non-empty string", func(s string) bool {
+		return s != ""
+	}))
+
+	uuid := regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	p.Register(UUID, formatCheck("uuid", uuid.MatchString))
+
+	p.Register(RFC3339, formatCheck("rfc3339 date-time", func(s string) bool {
+		_, err := time.Parse(time.RFC3339Nano, s)
+
+		return err == nil
+	}))
+
+	p.Register(Email, formatCheck("email", func(s string) bool {
+		a, err := mail.ParseAddress(s)
+
+		return err == nil && a.Address == s
+	}))
+
+	p.Register(URI, formatCheck("uri", func(s string) bool {
+		u, err := url.Parse(s)
+
+		return err == nil && u.Scheme != ""
+	}))
+
+	p.Register(Base64, formatCheck("base64", func(s string) bool {
+		if _, err := base64.StdEncoding.DecodeString(s); err == nil {
+			return true
+		}
+
+		_, err := base64.URLEncoding.DecodeString(s)
+
+		return err == nil
+	}))
+
+	return p
+}
+
+// Register adds placeholder to registry, existing placeholder with the same name is replaced.
+func (p *Placeholders) Register(name string, check PlaceholderFunc) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.items == nil {
+		p.items = make(map[string]PlaceholderFunc)
+	}
+
+	p.items[name] = check
+}
+
+// Get returns placeholder by name.
+func (p *Placeholders) Get(name string) (PlaceholderFunc, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	check, found := p.items[name]
+
+	return check, found
+}
+
+func typeCheck(expected string) PlaceholderFunc {
+	return func(actual interface{}) error {
+		if t := jsonType(actual); t != expected {
+			return fmt.Errorf("expected %s, got %s", expected, t)
+		}
+
+		return nil
+	}
+}
+
+func formatCheck(format string, valid func(s string) bool) PlaceholderFunc {
+	return func(actual interface{}) error {
+		s, ok := actual.(string)
+		if !ok {
+			return fmt.Errorf("expected %s, got %s", format, jsonType(actual))
+		}
+
+		if !valid(s) {
+			return fmt.Errorf("expected %s, got %q", format, s)
+		}
+
+		return nil
+	}
+}
+
+// jsonType returns JSON type name of a decoded value.
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number, float64, float32, int, int64, uint64:
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
@@ -0,0 +1,96 @@
+package assertjson_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/swaggest/assertjson"
+)
+
+func TestEqual_placeholders(t *testing.T) {
+	run(t, []testcase{
+		{`{"a": "<any-number>"}`, `{"a": 1.5}`, true},
+		{`{"a": "<any-number>"}`, `{"a": "1.5"}`, false},
+		{`{"a": "<any-string>"}`, `{"a": "abc"}`, true},
+		{`{"a": "<any-string>"}`, `{"a": null}`, false},
+		{`{"a": "<any-object>"}`, `{"a": {"b": 1}}`, true},
+		{`{"a": "<any-object>"}`, `{"a": null}`, false},
+		{`{"a": "<any-array>"}`, `{"a": [1]}`, true},
+		{`{"a": "<any-bool>"}`, `{"a": false}`, true},
+		{`{"a": "<non-empty-string>"}`, `{"a": "x"}`, true},
+		{`{"a": "<non-empty-string>"}`, `{"a": ""}`, false},
+		{`{"a": "<uuid>"}`, `{"a": "123e4567-e89b-12d3-a456-426614174000"}`, true},
+		{`{"a": "<uuid>"}`, `{"a": "123e4567"}`, false},
+		{`{"a": "<rfc3339>"}`, `{"a": "2018-08-01T00:01:02Z"}`, true},
+		{`{"a": "<rfc3339>"}`, `{"a": "2018-08-01T00:01:02.123+02:00"}`, true},
+		{`{"a": "<rfc3339>"}`, `{"a": "2018-08-01"}`, false},
+		{`{"a": "<email>"}`, `{"a": "bob@example.com"}`, true},
+		{`{"a": "<email>"}`, `{"a": "Bob <bob@example.com>"}`, false},
+		{`{"a": "<uri>"}`, `{"a": "https://example.com/a?b=c"}`, true},
+		{`{"a": "<uri>"}`, `{"a": "/a/b"}`, false},
+		{`{"a": "<base64>"}`, `{"a": "aGVsbG8="}`, true},
+		{`{"a": "<base64>"}`, `{"a": "hello!"}`, false},
+	}, assertjson.Equal)
+}
+
+func TestComparer_FailNotEqual_placeholderMismatch(t *testing.T) {
+	err := assertjson.FailNotEqual([]byte(`{"a": "<any-number>", "b": ["<uuid>"]}`), []byte(`{"a": "1", "b": ["abc"]}`))
+	assert.EqualError(t, err, `not equal:
+ {
+-  "a": "<any-number>",
++  "a": "1",
+   "b": [
+-    "<uuid>"
++    "abc"
+   ]
+ }
+/a: expected number, got string
+/b/0: expected uuid, got "abc"
+`)
+}
+
+func TestPlaceholders_Register(t *testing.T) {
+	p := assertjson.NewPlaceholders()
+	p.Register("<positive>", func(actual interface{}) error {
+		if n, ok := actual.(json.Number); !ok || n.String()[0] == '-' || n.String() == "0" {
+			return errors.New("positive number expected")
+		}
+
+		return nil
+	})
+
+	c := assertjson.Comparer{Placeholders: p}
+
+	assert.NoError(t, c.FailNotEqualMarshal([]byte(`{"a": "<positive>", "b": "<any-string>"}`),
+		map[string]interface{}{"a": 1.5, "b": "c"}))
+	assert.EqualError(t, c.FailNotEqualMarshal([]byte(`{"a": "<positive>"}`), map[string]interface{}{"a": "c"}),
+		"not equal:\n {\n-  \"a\": \"<positive>\"\n+  \"a\": \"c\"\n }\n/a: positive number expected\n")
+}
+
+func TestEqual_regexp(t *testing.T) {
+	run(t, []testcase{
+		{`{"a": "<regexp:^req-[0-9a-f]{8}$>"}`, `{"a": "req-0123abcd"}`, true},
+		{`{"a": "<regexp:^req-[0-9a-f]{8}$>"}`, `{"a": "req-0123abcx"}`, false},
+		{`{"a": "<regexp:^req-[0-9a-f]{8}$>"}`, `{"a": 123}`, false},
+		{`{"a": "<regexp:[>"}`, `{"a": "["}`, false},
+	}, assertjson.Equal)
+}
+
+func TestComparer_FailNotEqual_regexp(t *testing.T) {
+	c := assertjson.Comparer{RegexpPrefix: "~/", RegexpSuffix: "/"}
+
+	assert.NoError(t, c.FailNotEqual([]byte(`{"url": "~/^https://example\\.com/\\?token=[a-z]+$/"}`),
+		[]byte(`{"url": "https://example.com/?token=abc"}`)))
+
+	// Long pattern is shown as is instead of text diff.
+	assert.EqualError(t, c.FailNotEqual([]byte(`{"url": "~/^https://example\\.com/\\?token=[a-z]+$/"}`),
+		[]byte(`{"url": "https://example.com/?token=123"}`)), `not equal:
+ {
+-  "url": "~/^https://example\.com/\?token=[a-z]+$/"
++  "url": "https://example.com/?token=123"
+ }
+/url: "https://example.com/?token=123" does not match ^https://example\.com/\?token=[a-z]+$
+`)
+}
@@ -0,0 +1,37 @@
+package assertjson
+
+import "github.com/swaggest/assertjson/diff"
+
+// Result describes difference between expected and actual JSON documents.
+type Result struct {
+	// Deltas are differences that are not accepted by comparison rules.
+	Deltas []diff.Delta
+
+	// Changes is a flat list of changed locations.
+	Changes []diff.Change
+
+	// Diff is a rendered difference, empty if documents are equal.
+	Diff string
+}
+
+// Equal returns true if there is no difference.
+func (r *Result) Equal() bool {
+	return len(r.Deltas) == 0
+}
+
+// NotEqualError is returned when JSON documents are different.
+type NotEqualError struct {
+	Result *Result
+}
+
+// Error returns rendered difference.
+func (e *NotEqualError) Error() string {
+	return "not equal:\n" + e.Result.Diff
+}
+
+// Compare compares JSON payloads and returns difference.
+//
+// Error is returned if payloads can not be compared, for example if they are not valid JSON.
+func (c Comparer) Compare(expected, actual []byte) (*Result, error) {
+	return c.compareBytes(expected, actual, false)
+}
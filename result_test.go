@@ -0,0 +1,122 @@
+package assertjson_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/assertjson/diff"
+)
+
+func TestComparer_Compare(t *testing.T) {
+	c := assertjson.Comparer{IgnoreDiff: assertjson.IgnoreDiff}
+
+	res, err := c.Compare(
+		[]byte(`{"id": "<ignore-diff>", "a/b": 1, "users": [{"email": "a@x"}, {"email": "c@x"}], "old": true}`),
+		[]byte(`{"id": 123, "a/b": 2, "users": [{"email": "b@x"}, {"email": "c@x"}], "new": null}`),
+	)
+	require.NoError(t, err)
+	assert.False(t, res.Equal())
+	assert.Len(t, res.Deltas, 4)
+	assert.Equal(t, []diff.Change{
+		{Type: diff.ChangeModified, Path: "/a~1b", OldValue: int64(1), NewValue: int64(2)},
+		{Type: diff.ChangeDeleted, Path: "/old", OldValue: true},
+		{Type: diff.ChangeModified, Path: "/users/0/email", OldValue: "a@x", NewValue: "b@x"},
+		{Type: diff.ChangeAdded, Path: "/new", NewValue: nil},
+	}, res.Changes)
+	assert.Equal(t, ` {
+-  "a/b": 1,
++  "a/b": 2,
+   "id": "<ignore-diff>",
+-  "old": true,
+   "users": [
+     {
+-      "email": "a@x"
++      "email": "b@x"
+     },
+     {
+       "email": "c@x"
+     }
+   ]
++  "new": null
+ }
+`, res.Diff)
+
+	res, err = c.Compare([]byte(`{"a": [1, 2]}`), []byte(`{"a": [1, 2]}`))
+	require.NoError(t, err)
+	assert.True(t, res.Equal())
+	assert.Empty(t, res.Changes)
+	assert.Empty(t, res.Diff)
+
+	_, err = c.Compare([]byte(`{`), []byte(`{}`))
+	assert.Error(t, err)
+}
+
+func TestNotEqualError(t *testing.T) {
+	err := assertjson.FailNotEqual([]byte(`{"a": 1}`), []byte(`{"a": 2}`))
+
+	var ne *assertjson.NotEqualError
+
+	require.True(t, errors.As(err, &ne))
+	assert.Equal(t, "not equal:\n"+ne.Result.Diff, err.Error())
+	assert.Equal(t, []diff.Change{
+		{Type: diff.ChangeModified, Path: "/a", OldValue: int64(1), NewValue: int64(2)},
+	}, ne.Result.Changes)
+
+	err = assertjson.FailMismatch([]byte(`{"a": 1}`), []byte(`{"a": 2, "b": 3}`))
+	require.True(t, errors.As(err, &ne))
+	assert.Len(t, ne.Result.Changes, 1)
+}
+
+func TestComparer_Compare_arrayKeys(t *testing.T) {
+	c := assertjson.Comparer{IgnoreDiff: assertjson.IgnoreDiff}
+	c.DifferConfig.ArrayKeys = map[string]string{"/users": "id"}
+
+	res, err := c.Compare(
+		[]byte(`{"users":[{"id":1,"seen":"<ignore-diff>"},{"id":2},{"id":3,"name":"c","seen":"<ignore-diff>"}]}`),
+		[]byte(`{"users":[{"id":3,"name":"c","seen":1},{"id":1,"seen":2},{"id":4}]}`),
+	)
+	require.NoError(t, err)
+
+	var paths []string
+	for _, ch := range res.Changes {
+		paths = append(paths, string(ch.Type)+" "+ch.Path+" "+ch.From)
+	}
+
+	assert.Equal(t, []string{
+		"deleted /users/1 ",
+		"moved /users/1 /users/0",
+		"added /users/2 ",
+	}, paths)
+}
+
+func TestComparer_Compare_unorderedArrays(t *testing.T) {
+	c := assertjson.Comparer{}
+	c.DifferConfig.UnorderedArrayPaths = []string{"/tags"}
+
+	expected := []byte(`{"tags":["a","b",{"id":1,"v":"x"}]}`)
+	actual := []byte(`{"tags":["b",{"id":1,"v":"y"},"a"]}`)
+
+	res, err := c.Compare(expected, actual)
+	require.NoError(t, err)
+
+	var paths []string
+	for _, ch := range res.Changes {
+		paths = append(paths, ch.Path)
+	}
+
+	assert.Equal(t, []string{"/tags/1/v"}, paths)
+
+	// Index selector refers to position in actual array.
+	c.IgnorePaths = []string{"/tags/1/v"}
+	assert.NoError(t, c.FailNotEqual(expected, actual))
+
+	// Paired items that are out of order are not reported.
+	res, err = c.Compare([]byte(`{"tags":[1,2,3]}`), []byte(`{"tags":[3,2,1,4]}`))
+	require.NoError(t, err)
+	require.Len(t, res.Changes, 1)
+	assert.Equal(t, diff.ChangeAdded, res.Changes[0].Type)
+	assert.Equal(t, "/tags/3", res.Changes[0].Path)
+}
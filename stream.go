@@ -0,0 +1,111 @@
+package assertjson
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/swaggest/assertjson/diff"
+)
+
+// EqualReader compares two JSON documents from readers ignoring string values "<ignore-diff>".
+//
+// Documents are compared token by token and only different values are decoded, see Comparer.EqualReader.
+func EqualReader(t TestingT, expected, actual io.Reader, msgAndArgs ...interface{}) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	return defaultComparer.EqualReader(t, expected, actual, msgAndArgs...)
+}
+
+// FailNotEqualReader returns error if JSON documents from readers are different, nil otherwise.
+func FailNotEqualReader(expected, actual io.Reader) error {
+	return defaultComparer.FailNotEqualReader(expected, actual)
+}
+
+// EqualReader compares two JSON documents from readers.
+//
+// Large documents are compared within bounded memory: both documents are walked token by token and
+// only values that differ are decoded, see diff.Differ.CompareStreams for limits. Items of ordered arrays
+// are compared by position, so an inserted item makes following items different. Difference is
+// rendered as path summary, one line per change.
+//
+// Each reader must have a single JSON value. Strict validation of actual document is done while it is read.
+func (c Comparer) EqualReader(t TestingT, expected, actual io.Reader, msgAndArgs ...interface{}) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	err := c.FailNotEqualReader(expected, actual)
+	if err == nil {
+		return true
+	}
+
+	msg := err.Error()
+	msg = strings.ToUpper(msg[0:1]) + msg[1:]
+	assert.Fail(t, msg, msgAndArgs...)
+
+	return false
+}
+
+// FailNotEqualReader returns error if JSON documents from readers are different, nil otherwise.
+func (c Comparer) FailNotEqualReader(expected, actual io.Reader) error {
+	res, err := c.compareReaders(expected, actual)
+	if err != nil {
+		return err
+	}
+
+	if res.Equal() {
+		return nil
+	}
+
+	return &NotEqualError{Result: res}
+}
+
+func (c Comparer) compareReaders(expected, actual io.Reader) (*Result, error) {
+	c.Vars = c.varStore()
+
+	cmp, err := c.newComparison(false)
+	if err != nil {
+		return nil, err
+	}
+
+	var strict *strictStream
+
+	if c.Strict {
+		actual, strict = newStrictStream(actual)
+	}
+
+	d, err := cmp.differ.CompareStreams(expected, actual)
+
+	if strict != nil {
+		// Failure of validation is preferred to failure of comparison that can be caused by it.
+		if sErr := strict.result(); sErr != nil && (err == nil || isStrictErr(sErr)) {
+			return nil, fmt.Errorf("invalid actual: %w", sErr)
+		}
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare: %w", err)
+	}
+
+	if !d.Modified() {
+		return &Result{}, nil
+	}
+
+	deltas := cmp.filterDeltas(d.Deltas(), nil)
+	if len(deltas) == 0 {
+		return &Result{}, nil
+	}
+
+	changes := diff.Changes(deltas)
+	diffText := diff.NewPathSummaryFormatter(diff.PathSummaryFormatterConfig{}).FormatChanges(changes)
+
+	return &Result{
+		Deltas:  deltas,
+		Changes: changes,
+		Diff:    diffText + cmp.explanation(),
+	}, nil
+}
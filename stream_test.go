@@ -0,0 +1,100 @@
+package assertjson_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bool64/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+)
+
+func TestComparer_FailNotEqualReader(t *testing.T) {
+	v := &shared.Vars{}
+	v.Set("$owner", "Bob")
+
+	c := assertjson.Comparer{
+		IgnoreDiff:   assertjson.IgnoreDiff,
+		Placeholders: assertjson.DefaultPlaceholders,
+		Vars:         v,
+	}
+
+	expected := bytes.NewBufferString(`{"owner":"$owner","id":"$id","items":[`)
+	actual := bytes.NewBufferString(`{"owner":"Bob","id":12,"items":[`)
+
+	for i := 0; i < 1000; i++ {
+		if i > 0 {
+			expected.WriteString(",")
+			actual.WriteString(",")
+		}
+
+		switch i {
+		case 10:
+			expected.WriteString(`{"id":10,"name":"<any-string>","updatedAt":"<ignore-diff>"}`)
+			actual.WriteString(`{"id":10,"name":"item 10","updatedAt":"2021-01-01"}`)
+		case 500:
+			expected.WriteString(`{"id":500,"name":"item 500"}`)
+			actual.WriteString(`{"name":"item 501","id":500}`)
+		default:
+			_, _ = fmt.Fprintf(expected, `{"id":%d,"name":"item %d"}`, i, i)
+			_, _ = fmt.Fprintf(actual, `{"id":%d,"name":"item %d"}`, i, i)
+		}
+	}
+
+	expected.WriteString(`]}`)
+	actual.WriteString(`,{"id":1000}]}`)
+
+	err := c.FailNotEqualReader(expected, actual)
+
+	var ne *assertjson.NotEqualError
+
+	require.True(t, errors.As(err, &ne))
+	assert.Equal(t, `not equal:
+/items/500/name: "item 500" -> "item 501"
+/items/1000: added {"id":1000}
+collected variables:
+$id: 12
+`, err.Error())
+	assert.Len(t, ne.Result.Changes, 2)
+
+	id, found := v.Get("$id")
+	assert.True(t, found)
+	assert.Equal(t, int64(12), id)
+
+	assert.NoError(t, c.FailNotEqualReader(strings.NewReader(`{"id":"$id","owner":"$owner"}`),
+		strings.NewReader(`{"id":12,"owner":"Bob"}`)))
+	assert.EqualError(t, c.FailNotEqualReader(strings.NewReader(`{"id":"$id"}`), strings.NewReader(`{"id":13}`)),
+		"not equal:\n/id: \"$id\" -> 13\nvariable $id bound to 12 but found 13 at /id\n")
+}
+
+func TestEqualReader(t *testing.T) {
+	assert.True(t, assertjson.EqualReader(t, strings.NewReader(`{"a":[1,2,3],"b":"<ignore-diff>"}`),
+		strings.NewReader(`{"b":{"c":1},"a":[1,2,3]}`)))
+
+	assert.False(t, assertjson.EqualReader(testingT(func(format string, args ...interface{}) {
+		assert.Contains(t, args[0], "Failed to compare: failed to read right: unexpected end of JSON input")
+	}), strings.NewReader(`{}`), strings.NewReader(`{`)))
+}
+
+func TestComparer_FailNotEqualReader_varsTyped(t *testing.T) {
+	v := &shared.Vars{}
+	v.Set("$tags", []string{"a", "b"})
+	v.Set("$n", 1.5)
+
+	c := assertjson.Comparer{Vars: v}
+
+	// Expected stream is not substituted, so values of variables are compared with actual values.
+	assert.NoError(t, c.FailNotEqualReader(strings.NewReader(`{"tags":"$tags","n":"$n"}`),
+		strings.NewReader(`{"tags":["a","b"],"n":1.5}`)))
+	assert.EqualError(t, c.FailNotEqualReader(strings.NewReader(`{"tags":"$tags","n":"$n"}`),
+		strings.NewReader(`{"tags":["a"],"n":"1.5"}`)), `not equal:
+/tags: "$tags" -> ["a"]
+/n: "$n" -> "1.5"
+variable $tags bound to ["a","b"] but found ["a"] at /tags
+variable $n bound to 1.5 but found "1.5" at /n
+`)
+}
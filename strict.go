@@ -0,0 +1,178 @@
+package assertjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/swaggest/assertjson/diff"
+)
+
+// Errors of strict validation of actual payload.
+var (
+	ErrDuplicateKey = errors.New("duplicate key")
+	ErrTrailingData = diff.ErrTrailingData
+	ErrInvalidUTF8  = errors.New("invalid UTF-8")
+)
+
+// checkStrict validates that JSON payload has a single value without duplicate keys and invalid UTF-8.
+func checkStrict(data []byte) error {
+	return checkStrictReader(bytes.NewReader(data))
+}
+
+// checkStrictReader validates JSON stream, see checkStrict.
+func checkStrictReader(r io.Reader) error {
+	dec := json.NewDecoder(&utf8Reader{r: r})
+	dec.UseNumber()
+
+	if err := checkStrictValue(dec, nil); err != nil {
+		return err
+	}
+
+	offset := dec.InputOffset()
+
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		if errors.Is(err, ErrInvalidUTF8) {
+			return err
+		}
+
+		return fmt.Errorf("%w after offset %d", ErrTrailingData, offset)
+	}
+
+	return nil
+}
+
+// utf8Reader fails on invalid UTF-8 sequences of underlying reader.
+type utf8Reader struct {
+	r       io.Reader
+	offset  int    // Offset of pending bytes.
+	pending []byte // Incomplete rune at the end of previous read.
+	err     error
+}
+
+func (u *utf8Reader) Read(p []byte) (int, error) {
+	if u.err != nil {
+		return 0, u.err
+	}
+
+	n, err := u.r.Read(p)
+	data := append(u.pending, p[:n]...) //nolint:gocritic // Pending bytes are copied.
+	i := 0
+
+	for i < len(data) {
+		if err == nil && !utf8.FullRune(data[i:]) {
+			break // Waiting for the rest of rune.
+		}
+
+		r, size := utf8.DecodeRune(data[i:])
+		if r == utf8.RuneError && size == 1 {
+			u.err = fmt.Errorf("%w at offset %d", ErrInvalidUTF8, u.offset+i)
+
+			// Only valid bytes are passed, so that the error is not lost with a complete value.
+			valid := i - len(u.pending)
+			if valid < 0 {
+				valid = 0
+			}
+
+			return valid, u.err
+		}
+
+		i += size
+	}
+
+	u.offset += i
+	u.pending = append(u.pending[:0], data[i:]...)
+
+	return n, err
+}
+
+// strictStream validates JSON stream while it is being read by comparison.
+type strictStream struct {
+	w    *io.PipeWriter
+	done chan error
+}
+
+// newStrictStream returns reader that passes data of r to validation.
+func newStrictStream(r io.Reader) (io.Reader, *strictStream) {
+	pr, pw := io.Pipe()
+	s := &strictStream{w: pw, done: make(chan error, 1)}
+
+	go func() {
+		err := checkStrictReader(pr)
+		_, _ = io.Copy(ioutil.Discard, pr) // Draining rest of data after failure.
+
+		s.done <- err
+	}()
+
+	return io.TeeReader(r, pw), s
+}
+
+// result finishes validation of data that was read and returns its error.
+func (s *strictStream) result() error {
+	_ = s.w.Close()
+
+	return <-s.done
+}
+
+// isStrictErr checks if error is a failure of strict validation.
+func isStrictErr(err error) bool {
+	return errors.Is(err, ErrDuplicateKey) || errors.Is(err, ErrTrailingData) || errors.Is(err, ErrInvalidUTF8)
+}
+
+func checkStrictValue(dec *json.Decoder, path []string) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		keys := make(map[string]bool)
+
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+
+			key, ok := tok.(string)
+			if !ok {
+				return fmt.Errorf("unexpected token %v at %s", tok, diff.Pointer(path...))
+			}
+
+			keyPath := append(path[:len(path):len(path)], key)
+
+			if keys[key] {
+				return fmt.Errorf("%w %q at %s", ErrDuplicateKey, key, diff.Pointer(keyPath...))
+			}
+
+			keys[key] = true
+
+			if err := checkStrictValue(dec, keyPath); err != nil {
+				return err
+			}
+		}
+
+		_, err = dec.Token() // Closing '}'.
+
+		return err
+
+	case json.Delim('['):
+		for i := 0; dec.More(); i++ {
+			if err := checkStrictValue(dec, append(path[:len(path):len(path)], strconv.Itoa(i))); err != nil {
+				return err
+			}
+		}
+
+		_, err = dec.Token() // Closing ']'.
+
+		return err
+	}
+
+	return nil
+}
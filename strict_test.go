@@ -0,0 +1,59 @@
+package assertjson_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/swaggest/assertjson"
+)
+
+func TestComparer_FailNotEqual_strict(t *testing.T) {
+	c := assertjson.Comparer{Strict: true}
+
+	for _, tc := range []struct {
+		actual string
+		err    error
+		msg    string
+	}{
+		{
+			actual: `{"users":[{"id":1},{"id":2,"name":"Bob","id":3}]}`,
+			err:    assertjson.ErrDuplicateKey,
+			msg:    `invalid actual: duplicate key "id" at /users/1/id`,
+		},
+		{
+			actual: `{"id":1} {"id":2}`,
+			err:    assertjson.ErrTrailingData,
+			msg:    `invalid actual: trailing data after offset 8`,
+		},
+		{
+			actual: `{"id":1}]`,
+			err:    assertjson.ErrTrailingData,
+			msg:    `invalid actual: trailing data after offset 8`,
+		},
+		{
+			actual: "{\"id\":\"a\xffb\"}",
+			err:    assertjson.ErrInvalidUTF8,
+			msg:    `invalid actual: invalid UTF-8 at offset 8`,
+		},
+	} {
+		t.Run(tc.actual, func(t *testing.T) {
+			err := c.FailNotEqual([]byte(`"<ignore-diff>"`), []byte(tc.actual))
+			assert.True(t, errors.Is(err, tc.err))
+			assert.EqualError(t, err, tc.msg)
+
+			err = c.FailNotEqualReader(strings.NewReader(`"<ignore-diff>"`), strings.NewReader(tc.actual))
+			assert.True(t, errors.Is(err, tc.err))
+			assert.EqualError(t, err, tc.msg)
+
+			// Non-strict comparison accepts the payload.
+			assert.NoError(t, assertjson.Comparer{IgnoreDiff: assertjson.IgnoreDiff}.
+				FailNotEqual([]byte(`"<ignore-diff>"`), []byte(tc.actual)))
+		})
+	}
+
+	assert.NoError(t, c.FailNotEqual([]byte(`{"a":[1,{"b":"ü"}],"b":{"a":1}}`), []byte(` {"a":[1,{"b":"ü"}],"b":{"a":1}} `)))
+	assert.NoError(t, c.FailNotEqualReader(strings.NewReader(`{"a":[1,{"b":"ü"}],"b":{"a":1}}`),
+		strings.NewReader(` {"a":[1,{"b":"ü"}],"b":{"a":1}} `)))
+}
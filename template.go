@@ -0,0 +1,184 @@
+package assertjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// templateVar matches variable reference within a string, e.g. "${userId}" in "/users/${userId}/avatar".
+var templateVar = regexp.MustCompile(`\$\{([^{}]+)}`)
+
+// templateInteger matches captured values that are collected as numbers.
+var templateInteger = regexp.MustCompile(`^-?(0|[1-9][0-9]*)$`)
+
+// templateVarName returns name of variable referenced as "${name}".
+func (c Comparer) templateVarName(name string) string {
+	return c.varPrefix() + name
+}
+
+// isTemplate checks if string has variable references.
+func (c Comparer) isTemplate(s string) bool {
+	return c.VarTemplates && c.Vars != nil && strings.Contains(s, "${") && len(c.templateRefs(s)) > 0
+}
+
+// templateRefs returns submatch locations of references to names that are recognized as variables,
+// other references are treated as plain text.
+func (c Comparer) templateRefs(s string) [][]int {
+	var refs [][]int
+
+	for _, loc := range templateVar.FindAllStringSubmatchIndex(s, -1) {
+		if c.isVar(c.templateVarName(s[loc[2]:loc[3]])) {
+			refs = append(refs, loc)
+		}
+	}
+
+	return refs
+}
+
+// interpolate replaces references to known variables within a string, unknown references are kept.
+func (c Comparer) interpolate(s string) (string, error) {
+	var err error
+
+	res := templateVar.ReplaceAllStringFunc(s, func(ref string) string {
+		name := c.templateVarName(ref[2 : len(ref)-1])
+		if !c.isVar(name) {
+			return ref
+		}
+
+		value, found := c.Vars.Get(name)
+		if !found {
+			return ref
+		}
+
+		s, vErr := templateValue(value)
+		if vErr != nil {
+			err = fmt.Errorf("failed to marshal var %s: %w", name, vErr)
+
+			return ref
+		}
+
+		return s
+	})
+
+	return res, err
+}
+
+// knownTemplateVars returns names of known variables referenced within a string.
+func (c Comparer) knownTemplateVars(s string) []string {
+	var names []string
+
+	for _, loc := range c.templateRefs(s) {
+		name := c.templateVarName(s[loc[2]:loc[3]])
+
+		if _, found := c.Vars.Get(name); found {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// templateValue renders value of variable within a string.
+func templateValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	}
+
+	j, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return string(j), nil
+}
+
+// templateAccepted checks if actual value matches string with references to unknown variables
+// and collects values of variables.
+func (c *comparison) templateAccepted(s string, actual interface{}, path []string) bool {
+	a, ok := actual.(string)
+	if !ok {
+		c.mismatch(path, fmt.Sprintf("expected string matching %q, got %s", s, jsonType(actual)))
+
+		return false
+	}
+
+	var (
+		pattern strings.Builder
+		names   []string
+		prev    int
+	)
+
+	pattern.WriteString("^")
+
+	for _, loc := range c.templateRefs(s) {
+		pattern.WriteString(regexp.QuoteMeta(s[prev:loc[0]]))
+		pattern.WriteString("(.*?)")
+
+		names = append(names, c.templateVarName(s[loc[2]:loc[3]]))
+		prev = loc[1]
+	}
+
+	pattern.WriteString(regexp.QuoteMeta(s[prev:]) + "$")
+
+	m := regexp.MustCompile(pattern.String()).FindStringSubmatch(a)
+	if m == nil {
+		c.mismatch(path, fmt.Sprintf("%q does not match %q", a, s))
+
+		return false
+	}
+
+	values := make(map[string]string, len(names))
+
+	for i, name := range names {
+		if v, found := values[name]; found && v != m[i+1] {
+			c.mismatch(path, fmt.Sprintf("%q does not match %q, %s is both %q and %q", a, s, name, v, m[i+1]))
+
+			return false
+		}
+
+		values[name] = m[i+1]
+	}
+
+	// Variables collected earlier in the same comparison are checked.
+	for _, name := range names {
+		value, found := c.Vars.Get(name)
+		if !found {
+			continue
+		}
+
+		if s, err := templateValue(value); err != nil || s != values[name] {
+			c.varConflict(name, value, captureValue(values[name]), path)
+
+			return false
+		}
+	}
+
+	if !c.dryRun {
+		for _, name := range names {
+			if _, found := c.Vars.Get(name); !found {
+				c.Vars.Set(name, captureValue(values[name]))
+				c.collected = append(c.collected, name)
+			}
+		}
+	}
+
+	return true
+}
+
+// captureValue returns integer for captured digits, so that collected variable also matches a number,
+// other values are kept as strings.
+func captureValue(s string) interface{} {
+	if templateInteger.MatchString(s) {
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i
+		}
+	}
+
+	return s
+}
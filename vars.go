@@ -0,0 +1,62 @@
+package assertjson
+
+import (
+	"reflect"
+
+	"github.com/bool64/shared"
+)
+
+// VarStore keeps values of variables, *shared.Vars implements it.
+type VarStore interface {
+	// IsVar checks if string looks like a variable name.
+	IsVar(s string) bool
+
+	// Get returns variable value if it exists.
+	Get(s string) (interface{}, bool)
+
+	// Set sets variable by name.
+	Set(key string, val interface{})
+
+	// GetAll returns all variables with values.
+	GetAll() map[string]interface{}
+}
+
+var _ VarStore = &shared.Vars{}
+
+// varStore returns Vars, or nil if Vars is a nil pointer, map or func, e.g. nil *shared.Vars.
+func (c Comparer) varStore() VarStore {
+	if c.Vars == nil {
+		return nil
+	}
+
+	switch v := reflect.ValueOf(c.Vars); v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+	}
+
+	return c.Vars
+}
+
+// isVar checks if expected string is a variable reference.
+func (c Comparer) isVar(s string) bool {
+	if c.Vars == nil {
+		return false
+	}
+
+	if c.VarRecognizer != nil {
+		return c.VarRecognizer(s)
+	}
+
+	return c.Vars.IsVar(s)
+}
+
+// varPrefix returns prefix of variable names that are referenced as "${name}" within strings.
+func (c Comparer) varPrefix() string {
+	if v, ok := c.Vars.(*shared.Vars); ok && v.VarPrefix != "" {
+		return v.VarPrefix
+	}
+
+	return "$"
+}
@@ -0,0 +1,88 @@
+package assertjson_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bool64/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/swaggest/assertjson"
+)
+
+// scenarioVars is a variable store of a test scenario.
+type scenarioVars map[string]interface{}
+
+func (s scenarioVars) IsVar(name string) bool {
+	return strings.HasPrefix(name, "{{") && strings.HasSuffix(name, "}}")
+}
+
+func (s scenarioVars) Get(name string) (interface{}, bool) {
+	v, found := s[name]
+
+	return v, found
+}
+
+func (s scenarioVars) Set(name string, v interface{}) {
+	s[name] = v
+}
+
+func (s scenarioVars) GetAll() map[string]interface{} {
+	return s
+}
+
+func TestComparer_Equal_varStore(t *testing.T) {
+	vars := scenarioVars{"{{name}}": "Alice"}
+	c := assertjson.Comparer{Vars: vars}
+
+	c.Equal(t, []byte(`{"id":"{{id}}","name":"{{name}}","tag":"$tag"}`), []byte(`{"id":12,"name":"Alice","tag":"$tag"}`))
+	assert.Equal(t, int64(12), vars["{{id}}"])
+
+	assert.EqualError(t, c.FailNotEqual([]byte(`{"id":"{{id}}"}`), []byte(`{"id":13}`)), `not equal:
+ {
+-  "id": 12 // {{id}}
++  "id": 13
+ }
+`)
+}
+
+func TestComparer_Equal_varRecognizer(t *testing.T) {
+	vars := scenarioVars{}
+	c := assertjson.Comparer{
+		Vars:         vars,
+		VarTemplates: true,
+		VarRecognizer: func(s string) bool {
+			return len(s) > 1 && s[0] == ':'
+		},
+	}
+
+	// "${host}" refers to "$host" that is not recognized as a variable, so it is compared as plain text.
+	c.Equal(t, []byte(`{"id":":id","ids":[":id"],"url":"https://${host}/"}`),
+		[]byte(`{"id":"a","ids":["a"],"url":"https://${host}/"}`))
+	assert.Equal(t, scenarioVars{":id": "a"}, vars)
+
+	assert.EqualError(t, c.FailNotEqual([]byte(`{"url":"https://${host}/"}`), []byte(`{"url":"https://api/"}`)),
+		`not equal:
+ {
+-  "url": "https://${host}/"
++  "url": "https://api/"
+ }
+`)
+	assert.Equal(t, scenarioVars{":id": "a"}, vars)
+}
+
+func TestComparer_FailNotEqual_nilVars(t *testing.T) {
+	var (
+		v     *shared.Vars
+		store scenarioVars
+	)
+
+	for _, c := range []assertjson.Comparer{{Vars: v}, {Vars: store}} {
+		assert.NoError(t, c.FailNotEqual([]byte(`{"id":"$id"}`), []byte(`{"id":"$id"}`)))
+		assert.EqualError(t, c.FailNotEqual([]byte(`{"id":"$id"}`), []byte(`{"id":1}`)), `not equal:
+ {
+-  "id": "$id"
++  "id": 1
+ }
+`)
+	}
+}